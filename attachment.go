@@ -0,0 +1,144 @@
+package ken
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// AttachmentDownloadOptions specifies limits which
+// are applied when downloading an attachment with
+// DownloadAttachment.
+type AttachmentDownloadOptions struct {
+	// MaxSize is the maximum size of the attachment in
+	// bytes. When zero, the size is not limited.
+	MaxSize int64
+	// ContentTypes is a list of allowed media types like
+	// "application/json". A type can also be specified
+	// with a wildcard sub type like "image/*". When
+	// empty, all content types are allowed.
+	ContentTypes []string
+	// Client is the HTTP client used to download the
+	// attachment. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// DownloadAttachment checks the size and content type of
+// the passed attachment against the limits specified in
+// opt and returns a reader streaming the content of the
+// attachment.
+//
+// The limits are checked against the attachment metadata
+// before the download as well as against the response
+// while streaming. When the content exceeds MaxSize,
+// reading returns ErrAttachmentTooLarge.
+//
+// The returned reader must be closed after use.
+func DownloadAttachment(
+	ctx context.Context,
+	a *discordgo.MessageAttachment,
+	opt AttachmentDownloadOptions,
+) (r io.ReadCloser, err error) {
+	if a == nil || a.URL == "" {
+		return nil, ErrOptionNotResolved
+	}
+
+	if opt.MaxSize > 0 && int64(a.Size) > opt.MaxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+	if a.ContentType != "" && !contentTypeAllowed(a.ContentType, opt.ContentTypes) {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentContentType, a.ContentType)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.URL, nil)
+	if err != nil {
+		return
+	}
+
+	client := opt.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("attachment download failed with status %s", res.Status)
+	}
+	if opt.MaxSize > 0 && res.ContentLength > opt.MaxSize {
+		res.Body.Close()
+		return nil, ErrAttachmentTooLarge
+	}
+	if ct := res.Header.Get("Content-Type"); ct != "" && !contentTypeAllowed(ct, opt.ContentTypes) {
+		res.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentContentType, ct)
+	}
+
+	if opt.MaxSize <= 0 {
+		return res.Body, nil
+	}
+	return &limitedReadCloser{
+		rc:        res.Body,
+		remaining: opt.MaxSize,
+	}, nil
+}
+
+// limitedReadCloser returns ErrAttachmentTooLarge when
+// more than the remaining bytes are read from rc.
+type limitedReadCloser struct {
+	rc        io.ReadCloser
+	remaining int64
+}
+
+func (l *limitedReadCloser) Read(p []byte) (n int, err error) {
+	if l.remaining < 0 {
+		return 0, ErrAttachmentTooLarge
+	}
+	// One byte more than allowed is read to detect
+	// content exceeding the limit.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err = l.rc.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrAttachmentTooLarge
+	}
+	return
+}
+
+func (l *limitedReadCloser) Close() error {
+	return l.rc.Close()
+}
+
+func contentTypeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == mediaType {
+			return true
+		}
+		if strings.HasSuffix(a, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(a, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
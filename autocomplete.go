@@ -1,6 +1,8 @@
 package ken
 
 import (
+	"context"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/zekrotja/safepool"
 )
@@ -26,6 +28,7 @@ type AutocompleteContext struct {
 	session *discordgo.Session
 	ken     *Ken
 	event   *discordgo.InteractionCreate
+	ctx     context.Context
 }
 
 var _ safepool.ResetState = (*AutocompleteContext)(nil)
@@ -40,6 +43,7 @@ func (t *AutocompleteContext) ResetState() {
 	t.ken = nil
 	t.session = nil
 	t.event = nil
+	t.ctx = nil
 	t.Purge()
 }
 
@@ -64,6 +68,14 @@ func (t *AutocompleteContext) GetSession() *discordgo.Session {
 	return t.session
 }
 
+// Context returns the context.Context of the autocomplete
+// interaction. It is canceled on Shutdown of Ken, when the
+// response window of the interaction has passed or when
+// the handler has returned.
+func (t *AutocompleteContext) Context() context.Context {
+	return t.ctx
+}
+
 // GetKen returns the Ken instance.
 func (t *AutocompleteContext) GetKen() *Ken {
 	return t.ken
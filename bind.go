@@ -0,0 +1,430 @@
+package ken
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Struct tags which are used to declare command options
+// with OptionsFromStruct and to bind option values with
+// Ctx.Bind.
+//
+// The "ken" tag contains the name of the option followed
+// by the optional flags "required" and "autocomplete",
+// separated by commas. When the name is empty, the
+// lowercase field name is used. Fields tagged with
+// `ken:"-"` are skipped.
+//
+// The "description" tag contains the description of the
+// option.
+//
+// The "min" and "max" tags specify the minimum and maximum
+// value of integer and number options or the minimum and
+// maximum length of string options.
+//
+// The "choices" tag contains a comma separated list of
+// choices either as "name=value" pairs or as plain values,
+// which are then used as name as well.
+//
+// The "channels" tag contains a comma separated list of
+// channel types which can be selected for channel options.
+// Valid values are "text", "voice", "category", "news",
+// "news_thread", "public_thread", "private_thread",
+// "stage", "directory", "forum" and "media".
+//
+// Example:
+//
+//	type BanArgs struct {
+//		User   *discordgo.User `ken:"user,required" description:"The user to ban."`
+//		Days   int             `ken:"days" description:"Days of messages to delete." min:"0" max:"7"`
+//		Reason string          `ken:"reason" description:"The ban reason." choices:"Spam=spam,Abuse=abuse"`
+//	}
+const (
+	tagOption      = "ken"
+	tagDescription = "description"
+	tagMin         = "min"
+	tagMax         = "max"
+	tagChoices     = "choices"
+	tagChannels    = "channels"
+)
+
+var channelTypeNames = map[string]discordgo.ChannelType{
+	"text":           discordgo.ChannelTypeGuildText,
+	"voice":          discordgo.ChannelTypeGuildVoice,
+	"category":       discordgo.ChannelTypeGuildCategory,
+	"news":           discordgo.ChannelTypeGuildNews,
+	"news_thread":    discordgo.ChannelTypeGuildNewsThread,
+	"public_thread":  discordgo.ChannelTypeGuildPublicThread,
+	"private_thread": discordgo.ChannelTypeGuildPrivateThread,
+	"stage":          discordgo.ChannelTypeGuildStageVoice,
+	"directory":      discordgo.ChannelTypeGuildDirectory,
+	"forum":          discordgo.ChannelTypeGuildForum,
+	"media":          discordgo.ChannelTypeGuildMedia,
+}
+
+var (
+	typeUser       = reflect.TypeOf((*discordgo.User)(nil))
+	typeMember     = reflect.TypeOf((*discordgo.Member)(nil))
+	typeRole       = reflect.TypeOf((*discordgo.Role)(nil))
+	typeChannel    = reflect.TypeOf((*discordgo.Channel)(nil))
+	typeAttachment = reflect.TypeOf((*discordgo.MessageAttachment)(nil))
+)
+
+// structField is a struct field which declares a
+// command option.
+type structField struct {
+	index        int
+	name         string
+	required     bool
+	autocomplete bool
+	typ          discordgo.ApplicationCommandOptionType
+}
+
+// OptionsFromStruct generates the application command
+// options from the tagged fields of the passed struct
+// or pointer to a struct.
+//
+// Required options are placed before optional ones
+// because Discord requires this order.
+func OptionsFromStruct(v interface{}) (opts []*discordgo.ApplicationCommandOption, err error) {
+	typ := reflect.TypeOf(v)
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, ErrInvalidBindTarget
+	}
+
+	fields, err := structFields(typ)
+	if err != nil {
+		return
+	}
+
+	opts = make([]*discordgo.ApplicationCommandOption, 0, len(fields))
+	for _, f := range fields {
+		var opt *discordgo.ApplicationCommandOption
+		opt, err = structFieldOption(typ.Field(f.index), f)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+
+	sort.SliceStable(opts, func(i, j int) bool {
+		return opts[i].Required && !opts[j].Required
+	})
+
+	return
+}
+
+// MustOptionsFromStruct is like OptionsFromStruct but
+// panics when the options could not be generated.
+//
+// This can be used to implement the Options method of
+// a SlashCommand.
+func MustOptionsFromStruct(v interface{}) []*discordgo.ApplicationCommandOption {
+	opts, err := OptionsFromStruct(v)
+	if err != nil {
+		panic(err)
+	}
+	return opts
+}
+
+// bind sets the values of the passed options to the
+// tagged fields of the struct v points to.
+func (co CommandOptions) bind(ctx Context, v interface{}) (err error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidBindTarget
+	}
+	rv = rv.Elem()
+
+	fields, err := structFields(rv.Type())
+	if err != nil {
+		return
+	}
+
+	for _, f := range fields {
+		opt, ok := co.GetByNameOptional(f.name)
+		if !ok {
+			continue
+		}
+		if opt.Type != f.typ {
+			return fmt.Errorf("%w: option %q is of type %s but field is of type %s",
+				ErrInvalidBindTarget, f.name, opt.Type, f.typ)
+		}
+		if err = setFieldValue(ctx, rv.Field(f.index), opt); err != nil {
+			return fmt.Errorf("option %q: %w", f.name, err)
+		}
+	}
+
+	return
+}
+
+func structFields(typ reflect.Type) (fields []structField, err error) {
+	fields = make([]structField, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		tag, hasTag := sf.Tag.Lookup(tagOption)
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+
+		optType, ok := optionTypeOf(sf.Type)
+		if !ok {
+			if !hasTag {
+				continue
+			}
+			return nil, fmt.Errorf("%w: field %s has unsupported type %s",
+				ErrInvalidBindTarget, sf.Name, sf.Type)
+		}
+
+		f := structField{
+			index: i,
+			name:  strings.ToLower(sf.Name),
+			typ:   optType,
+		}
+
+		split := strings.Split(tag, ",")
+		if split[0] != "" {
+			f.name = split[0]
+		}
+		for _, flag := range split[1:] {
+			switch strings.TrimSpace(flag) {
+			case "required":
+				f.required = true
+			case "autocomplete":
+				f.autocomplete = true
+			default:
+				return nil, fmt.Errorf("%w: field %s has unknown flag %q",
+					ErrInvalidBindTarget, sf.Name, flag)
+			}
+		}
+
+		fields = append(fields, f)
+	}
+	return
+}
+
+func optionTypeOf(typ reflect.Type) (discordgo.ApplicationCommandOptionType, bool) {
+	switch typ {
+	case typeUser, typeMember:
+		return discordgo.ApplicationCommandOptionUser, true
+	case typeRole:
+		return discordgo.ApplicationCommandOptionRole, true
+	case typeChannel:
+		return discordgo.ApplicationCommandOptionChannel, true
+	case typeAttachment:
+		return discordgo.ApplicationCommandOptionAttachment, true
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return discordgo.ApplicationCommandOptionString, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return discordgo.ApplicationCommandOptionInteger, true
+	case reflect.Float32, reflect.Float64:
+		return discordgo.ApplicationCommandOptionNumber, true
+	case reflect.Bool:
+		return discordgo.ApplicationCommandOptionBoolean, true
+	}
+
+	return 0, false
+}
+
+func structFieldOption(sf reflect.StructField, f structField) (opt *discordgo.ApplicationCommandOption, err error) {
+	opt = &discordgo.ApplicationCommandOption{
+		Type:         f.typ,
+		Name:         f.name,
+		Description:  sf.Tag.Get(tagDescription),
+		Required:     f.required,
+		Autocomplete: f.autocomplete,
+	}
+
+	if opt.Description == "" {
+		return nil, fmt.Errorf("%w: option %q has no description", ErrInvalidBindTarget, f.name)
+	}
+
+	if err = setOptionLimits(opt, sf.Tag); err != nil {
+		return nil, fmt.Errorf("option %q: %w", f.name, err)
+	}
+
+	if choices, ok := sf.Tag.Lookup(tagChoices); ok {
+		if opt.Choices, err = parseChoices(f.typ, choices); err != nil {
+			return nil, fmt.Errorf("option %q: %w", f.name, err)
+		}
+	}
+
+	if channels, ok := sf.Tag.Lookup(tagChannels); ok {
+		if f.typ != discordgo.ApplicationCommandOptionChannel {
+			return nil, fmt.Errorf("%w: channel types set on non-channel option %q",
+				ErrInvalidBindTarget, f.name)
+		}
+		for _, name := range strings.Split(channels, ",") {
+			ct, ok := channelTypeNames[strings.TrimSpace(name)]
+			if !ok {
+				return nil, fmt.Errorf("%w: option %q has unknown channel type %q",
+					ErrInvalidBindTarget, f.name, name)
+			}
+			opt.ChannelTypes = append(opt.ChannelTypes, ct)
+		}
+	}
+
+	return
+}
+
+func setOptionLimits(opt *discordgo.ApplicationCommandOption, tag reflect.StructTag) error {
+	for _, key := range []string{tagMin, tagMax} {
+		v, ok := tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		switch opt.Type {
+		case discordgo.ApplicationCommandOptionInteger, discordgo.ApplicationCommandOptionNumber:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return err
+			}
+			if key == tagMin {
+				opt.MinValue = &f
+			} else {
+				opt.MaxValue = f
+			}
+		case discordgo.ApplicationCommandOptionString:
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return err
+			}
+			if key == tagMin {
+				opt.MinLength = &n
+			} else {
+				opt.MaxLength = n
+			}
+		default:
+			return fmt.Errorf("%w: %s is not supported for options of type %s",
+				ErrInvalidBindTarget, key, opt.Type)
+		}
+	}
+	return nil
+}
+
+func parseChoices(typ discordgo.ApplicationCommandOptionType, v string) (choices []*discordgo.ApplicationCommandOptionChoice, err error) {
+	for _, c := range strings.Split(v, ",") {
+		name, value := c, c
+		if i := strings.IndexRune(c, '='); i != -1 {
+			name, value = c[:i], c[i+1:]
+		}
+
+		choice := &discordgo.ApplicationCommandOptionChoice{
+			Name: strings.TrimSpace(name),
+		}
+		value = strings.TrimSpace(value)
+
+		switch typ {
+		case discordgo.ApplicationCommandOptionString:
+			choice.Value = value
+		case discordgo.ApplicationCommandOptionInteger:
+			choice.Value, err = strconv.ParseInt(value, 10, 64)
+		case discordgo.ApplicationCommandOptionNumber:
+			choice.Value, err = strconv.ParseFloat(value, 64)
+		default:
+			err = fmt.Errorf("%w: choices are not supported for options of type %s",
+				ErrInvalidBindTarget, typ)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		choices = append(choices, choice)
+	}
+	return
+}
+
+// setFieldValue sets the value of the passed option to
+// the field fv. Users, roles and channels are taken from
+// the resolved data of the interaction, if available.
+// Members are only set when they have been resolved.
+// Attachments must be present in the resolved data.
+func setFieldValue(ctx Context, fv reflect.Value, opt *CommandOption) error {
+	switch fv.Type() {
+	case typeUser, typeMember, typeRole, typeChannel, typeAttachment:
+		id, ok := opt.Value.(string)
+		if !ok {
+			return opt.valueTypeMismatch()
+		}
+		switch fv.Type() {
+		case typeUser:
+			fv.Set(reflect.ValueOf(resolveUser(ctx, id)))
+		case typeMember:
+			if member, ok := resolveMember(ctx, id); ok {
+				fv.Set(reflect.ValueOf(member))
+			}
+		case typeRole:
+			fv.Set(reflect.ValueOf(resolveRole(ctx, id)))
+		case typeChannel:
+			fv.Set(reflect.ValueOf(resolveChannel(ctx, id)))
+		case typeAttachment:
+			attachment, ok := resolvedData(ctx).Attachments[id]
+			if !ok {
+				return fmt.Errorf("%w: attachment %s", ErrOptionNotResolved, id)
+			}
+			fv.Set(reflect.ValueOf(attachment))
+		}
+		return nil
+	}
+
+	if fv.Kind() == reflect.Ptr {
+		fv.Set(reflect.New(fv.Type().Elem()))
+		fv = fv.Elem()
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		v, ok := opt.Value.(string)
+		if !ok {
+			return opt.valueTypeMismatch()
+		}
+		fv.SetString(strings.ReplaceAll(v, "\\n", "\n"))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !isNumber(opt.Value) {
+			return opt.valueTypeMismatch()
+		}
+		v := int64(toFloat64(opt.Value))
+		if fv.OverflowInt(v) {
+			return fmt.Errorf("value %d overflows %s", v, fv.Type())
+		}
+		fv.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !isNumber(opt.Value) {
+			return opt.valueTypeMismatch()
+		}
+		v := int64(toFloat64(opt.Value))
+		if v < 0 || fv.OverflowUint(uint64(v)) {
+			return fmt.Errorf("value %d overflows %s", v, fv.Type())
+		}
+		fv.SetUint(uint64(v))
+	case reflect.Float32, reflect.Float64:
+		if !isNumber(opt.Value) {
+			return opt.valueTypeMismatch()
+		}
+		fv.SetFloat(toFloat64(opt.Value))
+	case reflect.Bool:
+		v, ok := opt.Value.(bool)
+		if !ok {
+			return opt.valueTypeMismatch()
+		}
+		fv.SetBool(v)
+	}
+
+	return nil
+}
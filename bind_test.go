@@ -0,0 +1,311 @@
+package ken
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestOptionsFromStruct(t *testing.T) {
+	float64Ptr := func(v float64) *float64 { return &v }
+	intPtr := func(v int) *int { return &v }
+
+	type banArgs struct {
+		Reason  string             `ken:"reason" description:"The reason." min:"3" max:"100" choices:"Spam=spam, Abuse"`
+		User    *discordgo.User    `ken:"user,required" description:"The user."`
+		Days    *int               `ken:",autocomplete" description:"The days." min:"0" max:"7"`
+		Ratio   float64            `ken:"ratio" description:"The ratio." choices:"Half=0.5"`
+		Channel *discordgo.Channel `ken:"channel,required" description:"The channel." channels:"text, forum"`
+		Skipped string             `ken:"-"`
+		Other   []string
+		hidden  string
+	}
+
+	cases := []struct {
+		name string
+		v    interface{}
+		want []*discordgo.ApplicationCommandOption
+		err  error
+	}{
+		{
+			name: "tagged fields",
+			v:    &banArgs{},
+			want: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "The user.",
+					Required:    true,
+				},
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "The channel.",
+					Required:     true,
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildForum},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "reason",
+					Description: "The reason.",
+					MinLength:   intPtr(3),
+					MaxLength:   100,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Spam", Value: "spam"},
+						{Name: "Abuse", Value: "Abuse"},
+					},
+				},
+				{
+					Type:         discordgo.ApplicationCommandOptionInteger,
+					Name:         "days",
+					Description:  "The days.",
+					Autocomplete: true,
+					MinValue:     float64Ptr(0),
+					MaxValue:     7,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionNumber,
+					Name:        "ratio",
+					Description: "The ratio.",
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Half", Value: 0.5},
+					},
+				},
+			},
+		},
+		{
+			name: "struct value",
+			v: struct {
+				Silent bool `description:"Silent."`
+			}{},
+			want: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "silent", Description: "Silent."},
+			},
+		},
+		{
+			name: "no struct",
+			v:    "args",
+			err:  ErrInvalidBindTarget,
+		},
+		{
+			name: "nil",
+			err:  ErrInvalidBindTarget,
+		},
+		{
+			name: "tagged field of unsupported type",
+			v: &struct {
+				Names []string `ken:"names" description:"Names."`
+			}{},
+			err: ErrInvalidBindTarget,
+		},
+		{
+			name: "unknown flag",
+			v: &struct {
+				Name string `ken:"name,optional" description:"Name."`
+			}{},
+			err: ErrInvalidBindTarget,
+		},
+		{
+			name: "missing description",
+			v: &struct {
+				Name string `ken:"name"`
+			}{},
+			err: ErrInvalidBindTarget,
+		},
+		{
+			name: "limit on boolean",
+			v: &struct {
+				Silent bool `ken:"silent" description:"Silent." min:"1"`
+			}{},
+			err: ErrInvalidBindTarget,
+		},
+		{
+			name: "invalid limit",
+			v: &struct {
+				Name string `ken:"name" description:"Name." max:"many"`
+			}{},
+		},
+		{
+			name: "choices on boolean",
+			v: &struct {
+				Silent bool `ken:"silent" description:"Silent." choices:"yes"`
+			}{},
+			err: ErrInvalidBindTarget,
+		},
+		{
+			name: "invalid integer choice",
+			v: &struct {
+				Days int `ken:"days" description:"Days." choices:"Half=0.5"`
+			}{},
+		},
+		{
+			name: "channel types on non-channel",
+			v: &struct {
+				Name string `ken:"name" description:"Name." channels:"text"`
+			}{},
+			err: ErrInvalidBindTarget,
+		},
+		{
+			name: "unknown channel type",
+			v: &struct {
+				Channel *discordgo.Channel `ken:"channel" description:"Channel." channels:"dm"`
+			}{},
+			err: ErrInvalidBindTarget,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts, err := OptionsFromStruct(c.v)
+			if c.want == nil {
+				if err == nil {
+					t.Fatalf("expected error, got options %+v", opts)
+				}
+				if c.err != nil && !errors.Is(err, c.err) {
+					t.Fatalf("expected error %v, got %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(opts, c.want) {
+				t.Errorf("expected options %s, got %s", mustToJson(c.want), mustToJson(opts))
+			}
+		})
+	}
+}
+
+func TestCtxBind(t *testing.T) {
+	type args struct {
+		Name    string                       `ken:"name"`
+		Count   int8                         `ken:"count"`
+		Limit   *uint                        `ken:"limit"`
+		Ratio   float64                      `ken:"ratio"`
+		Silent  bool                         `ken:"silent"`
+		User    *discordgo.User              `ken:"user"`
+		Member  *discordgo.Member            `ken:"member"`
+		File    *discordgo.MessageAttachment `ken:"file"`
+		Ignored string                       `ken:"-"`
+	}
+
+	uintPtr := func(v uint) *uint { return &v }
+
+	cases := []struct {
+		name    string
+		options []*discordgo.ApplicationCommandInteractionDataOption
+		target  func() interface{}
+		want    interface{}
+		err     error
+	}{
+		{
+			name: "values",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "name", Type: discordgo.ApplicationCommandOptionString, Value: `a\nb`},
+				{Name: "count", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(-3)},
+				{Name: "limit", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(10)},
+				{Name: "ratio", Type: discordgo.ApplicationCommandOptionNumber, Value: 0.5},
+				{Name: "silent", Type: discordgo.ApplicationCommandOptionBoolean, Value: true},
+				{Name: "user", Type: discordgo.ApplicationCommandOptionUser, Value: "20"},
+				{Name: "member", Type: discordgo.ApplicationCommandOptionUser, Value: "21"},
+				{Name: "file", Type: discordgo.ApplicationCommandOptionAttachment, Value: "40"},
+				{Name: "ignored", Type: discordgo.ApplicationCommandOptionString, Value: "ignored"},
+			},
+			target: func() interface{} { return &args{} },
+			want: &args{
+				Name:   "a\nb",
+				Count:  -3,
+				Limit:  uintPtr(10),
+				Ratio:  0.5,
+				Silent: true,
+				User:   &discordgo.User{ID: "20", Username: "user"},
+				File:   &discordgo.MessageAttachment{ID: "40"},
+			},
+		},
+		{
+			name:   "missing options keep their values",
+			target: func() interface{} { return &args{Name: "name"} },
+			want:   &args{Name: "name"},
+		},
+		{
+			name: "option of other type",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "count", Type: discordgo.ApplicationCommandOptionString, Value: "3"},
+			},
+			target: func() interface{} { return &args{} },
+			err:    ErrInvalidBindTarget,
+		},
+		{
+			name: "value of other type",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "silent", Type: discordgo.ApplicationCommandOptionBoolean, Value: "true"},
+			},
+			target: func() interface{} { return &args{} },
+			err:    ErrOptionTypeMismatch,
+		},
+		{
+			name: "overflowing integer",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "count", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(300)},
+			},
+			target: func() interface{} { return &args{} },
+		},
+		{
+			name: "negative unsigned integer",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "limit", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(-1)},
+			},
+			target: func() interface{} { return &args{} },
+		},
+		{
+			name: "unresolved attachment",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "file", Type: discordgo.ApplicationCommandOptionAttachment, Value: "41"},
+			},
+			target: func() interface{} { return &args{} },
+			err:    ErrOptionNotResolved,
+		},
+		{
+			name:   "no pointer",
+			target: func() interface{} { return args{} },
+			err:    ErrInvalidBindTarget,
+		},
+		{
+			name:   "nil pointer",
+			target: func() interface{} { return (*args)(nil) },
+			err:    ErrInvalidBindTarget,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := newTestCtx(t, discordgo.ApplicationCommandInteractionData{
+				Options: c.options,
+				Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+					Users:       map[string]*discordgo.User{"20": {ID: "20", Username: "user"}},
+					Attachments: map[string]*discordgo.MessageAttachment{"40": {ID: "40"}},
+				},
+			})
+
+			v := c.target()
+			err := ctx.Bind(v)
+			if c.want == nil {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", v)
+				}
+				if c.err != nil && !errors.Is(err, c.err) {
+					t.Fatalf("expected error %v, got %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, v)
+			}
+		})
+	}
+}
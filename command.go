@@ -2,6 +2,7 @@ package ken
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -9,7 +10,10 @@ import (
 // Command specifies the base interface for an
 // application command.
 type Command interface {
-	// Name returns the unique name of the command.
+	// Name returns the name of the command.
+	//
+	// The name must be unique for each command type
+	// in the scope the command is registered in.
 	Name() string
 
 	// Description returns a brief text which concisely
@@ -39,20 +43,158 @@ type GuildScopedCommand interface {
 	Guild() string
 }
 
-func toApplicationCommand(c Command) *discordgo.ApplicationCommand {
+// MultiGuildScopedCommand can be implemented by your
+// commands to scope them to multiple guilds at once.
+//
+// The command then will be registered on each guild
+// returned by the Guilds method. When implemented, it
+// takes precedence over GuildScopedCommand.
+type MultiGuildScopedCommand interface {
+	Guilds() []string
+}
+
+// PermissionedCommand can be implemented by your
+// commands to specify the permissions a member needs
+// to have by default to see and use the command.
+//
+// Guild administrators can still override the
+// permissions of the command in the guild settings.
+type PermissionedCommand interface {
+	// DefaultMemberPermissions returns the permission
+	// bit set a member needs to have by default to be
+	// able to use the command.
+	DefaultMemberPermissions() int64
+}
+
+// NSFWCommand can be implemented by your commands to
+// mark them as age-restricted. Such commands will only
+// be available in NSFW channels.
+type NSFWCommand interface {
+	// IsNSFW returns true if the command is
+	// age-restricted.
+	IsNSFW() bool
+}
+
+// ContextAwareCommand can be implemented by your commands
+// to specify the contexts in which they can be used as
+// well as the installation contexts in which they are
+// available.
+//
+// When implemented, the returned Contexts take precedence
+// over the DMPermission which is derived from DmCapable.
+type ContextAwareCommand interface {
+	// Contexts returns the interaction contexts in which
+	// the command can be used. If nil is returned, the
+	// command can be used in all contexts.
+	Contexts() []discordgo.InteractionContextType
+
+	// IntegrationTypes returns the installation contexts
+	// in which the command is available. If nil is returned,
+	// the default installation contexts of the application
+	// are used.
+	IntegrationTypes() []discordgo.ApplicationIntegrationType
+}
+
+// optionalCommand is implemented by commands which
+// implement optional command interfaces without
+// necessarily specifying their values, like commands
+// built with the CommandBuilder.
+//
+// Optional interfaces for which implements returns
+// false are handled as if they were not implemented.
+type optionalCommand interface {
+	implements(iface reflect.Type) bool
+}
+
+// asOptional returns c as the optional command
+// interface T if c implements T and, if c is an
+// optionalCommand, reports T as implemented.
+func asOptional[T any](c Command) (v T, ok bool) {
+	if v, ok = c.(T); !ok {
+		return
+	}
+	if oc, isOptional := c.(optionalCommand); isOptional && !oc.implements(typeOf[T]()) {
+		var zero T
+		return zero, false
+	}
+	return
+}
+
+// typeOf returns the type of the interface T.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// isDmCapable returns true if the passed command can be
+// executed in DMs.
+//
+// Like on registration, the Contexts of a
+// ContextAwareCommand take precedence over DmCapable.
+func isDmCapable(c Command) bool {
+	if caCmd, ok := asOptional[ContextAwareCommand](c); ok {
+		if contexts := caCmd.Contexts(); contexts != nil {
+			return containsDMContext(contexts)
+		}
+	}
+	if dmCmd, ok := asOptional[DmCapable](c); ok {
+		return dmCmd.IsDmCapable()
+	}
+	return false
+}
+
+// containsDMContext returns true if the passed contexts
+// contain a context outside of guilds.
+func containsDMContext(contexts []discordgo.InteractionContextType) bool {
+	for _, ctx := range contexts {
+		if ctx == discordgo.InteractionContextBotDM || ctx == discordgo.InteractionContextPrivateChannel {
+			return true
+		}
+	}
+	return false
+}
+
+// commandGuilds returns the IDs of the guilds the
+// passed command is scoped to. A global command is
+// represented by a single empty guild ID.
+func commandGuilds(c Command) []string {
+	if mgsc, ok := asOptional[MultiGuildScopedCommand](c); ok {
+		if guilds := mgsc.Guilds(); len(guilds) > 0 {
+			return guilds
+		}
+	}
+	if gsc, ok := asOptional[GuildScopedCommand](c); ok {
+		return []string{gsc.Guild()}
+	}
+	return []string{""}
+}
+
+// commandType returns the application command type of
+// the passed command.
+func commandType(c Command) discordgo.ApplicationCommandType {
+	switch c.(type) {
+	case UserCommand:
+		return discordgo.UserApplicationCommand
+	case MessageCommand:
+		return discordgo.MessageApplicationCommand
+	default:
+		return discordgo.ChatApplicationCommand
+	}
+}
+
+func (k *Ken) toApplicationCommand(c Command) (acmd *discordgo.ApplicationCommand) {
 	switch cm := c.(type) {
 	case UserCommand:
-		return &discordgo.ApplicationCommand{
+		acmd = &discordgo.ApplicationCommand{
 			Name: cm.Name(),
 			Type: discordgo.UserApplicationCommand,
 		}
 	case MessageCommand:
-		return &discordgo.ApplicationCommand{
+		acmd = &discordgo.ApplicationCommand{
 			Name: cm.Name(),
 			Type: discordgo.MessageApplicationCommand,
 		}
 	case SlashCommand:
-		return &discordgo.ApplicationCommand{
+		acmd = &discordgo.ApplicationCommand{
 			Name:        cm.Name(),
 			Type:        discordgo.ChatApplicationCommand,
 			Description: cm.Description(),
@@ -62,4 +204,33 @@ func toApplicationCommand(c Command) *discordgo.ApplicationCommand {
 	default:
 		panic(fmt.Sprintf("Command type not implemented for command: %s", cm.Name()))
 	}
+
+	if pCmd, ok := asOptional[PermissionedCommand](c); ok {
+		perms := pCmd.DefaultMemberPermissions()
+		acmd.DefaultMemberPermissions = &perms
+	}
+
+	if nsfwCmd, ok := asOptional[NSFWCommand](c); ok {
+		nsfw := nsfwCmd.IsNSFW()
+		acmd.NSFW = &nsfw
+	}
+
+	var contexts []discordgo.InteractionContextType
+	if caCmd, ok := asOptional[ContextAwareCommand](c); ok {
+		contexts = caCmd.Contexts()
+		if contexts != nil {
+			acmd.Contexts = &contexts
+		}
+		if integrationTypes := caCmd.IntegrationTypes(); integrationTypes != nil {
+			acmd.IntegrationTypes = &integrationTypes
+		}
+	}
+	if contexts == nil {
+		dmPermission := isDmCapable(c)
+		acmd.DMPermission = &dmPermission
+	}
+
+	localizeApplicationCommand(acmd, c, k.opt.CommandCatalog)
+
+	return
 }
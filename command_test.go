@@ -0,0 +1,47 @@
+package ken
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type testDmCommand struct {
+	dmCapable bool
+	contexts  []discordgo.InteractionContextType
+}
+
+func (c *testDmCommand) Name() string          { return "test" }
+func (c *testDmCommand) Description() string   { return "test" }
+func (c *testDmCommand) Run(ctx Context) error { return nil }
+func (c *testDmCommand) IsDmCapable() bool     { return c.dmCapable }
+func (c *testDmCommand) Contexts() []discordgo.InteractionContextType {
+	return c.contexts
+}
+func (c *testDmCommand) IntegrationTypes() []discordgo.ApplicationIntegrationType {
+	return nil
+}
+
+func TestIsDmCapable(t *testing.T) {
+	cases := []struct {
+		name string
+		cmd  *testDmCommand
+		want bool
+	}{
+		{"no contexts, not dm capable", &testDmCommand{}, false},
+		{"no contexts, dm capable", &testDmCommand{dmCapable: true}, true},
+		{"dm context overrides DmCapable", &testDmCommand{
+			contexts: []discordgo.InteractionContextType{discordgo.InteractionContextBotDM},
+		}, true},
+		{"guild context overrides DmCapable", &testDmCommand{
+			dmCapable: true,
+			contexts:  []discordgo.InteractionContextType{discordgo.InteractionContextGuild},
+		}, false},
+	}
+
+	for _, c := range cases {
+		if got := isDmCapable(c.cmd); got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+}
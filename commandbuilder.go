@@ -0,0 +1,353 @@
+package ken
+
+import (
+	"reflect"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CommandBuilder builds a command from functions
+// without the need to declare a type implementing
+// the command interfaces.
+//
+//	ping := ken.NewSlash("ping").
+//		Description("Ping pong").
+//		DmCapable(true).
+//		Handler(func(ctx ken.Context) error {
+//			return ctx.RespondMessage("Pong!")
+//		})
+//
+// Optional properties which have not been set are
+// handled as if the corresponding optional command
+// interface has not been implemented.
+type CommandBuilder struct {
+	typ discordgo.ApplicationCommandType
+	def commandDefinition
+}
+
+// NewSlash returns a CommandBuilder for a slash
+// command with the given name.
+func NewSlash(name string) *CommandBuilder {
+	return newCommandBuilder(discordgo.ChatApplicationCommand, name)
+}
+
+// NewUser returns a CommandBuilder for a user
+// command with the given name.
+func NewUser(name string) *CommandBuilder {
+	return newCommandBuilder(discordgo.UserApplicationCommand, name)
+}
+
+// NewMessage returns a CommandBuilder for a message
+// command with the given name.
+func NewMessage(name string) *CommandBuilder {
+	return newCommandBuilder(discordgo.MessageApplicationCommand, name)
+}
+
+func newCommandBuilder(typ discordgo.ApplicationCommandType, name string) *CommandBuilder {
+	return &CommandBuilder{
+		typ: typ,
+		def: commandDefinition{
+			name:    name,
+			version: "1.0.0",
+		},
+	}
+}
+
+// Description sets the description of the command.
+//
+// This is ignored by user and message commands.
+func (b *CommandBuilder) Description(description string) *CommandBuilder {
+	b.def.description = description
+	return b
+}
+
+// Version sets the version of the command. Defaults
+// to "1.0.0".
+//
+// This is ignored by user and message commands.
+func (b *CommandBuilder) Version(version string) *CommandBuilder {
+	b.def.version = version
+	return b
+}
+
+// Option adds the passed options to the command.
+//
+// This is ignored by user and message commands.
+func (b *CommandBuilder) Option(options ...*discordgo.ApplicationCommandOption) *CommandBuilder {
+	b.def.options = append(b.def.options, options...)
+	return b
+}
+
+// Autocomplete sets the handler called on autocomplete
+// events of the command.
+//
+// This is ignored by user and message commands.
+func (b *CommandBuilder) Autocomplete(
+	handler func(ctx *AutocompleteContext) ([]*discordgo.ApplicationCommandOptionChoice, error),
+) *CommandBuilder {
+	b.def.autocomplete = handler
+	return b
+}
+
+// DmCapable sets whether the command can be
+// executed in DMs.
+//
+// See DmCapable for more information.
+func (b *CommandBuilder) DmCapable(dmCapable bool) *CommandBuilder {
+	b.def.dmCapable = dmCapable
+	return b
+}
+
+// Guilds scopes the command to the guilds with the
+// given IDs.
+//
+// See MultiGuildScopedCommand for more information.
+func (b *CommandBuilder) Guilds(guildIDs ...string) *CommandBuilder {
+	b.def.guilds = append(b.def.guilds, guildIDs...)
+	return b
+}
+
+// Guild scopes the command to the guild with the
+// given ID. Previously set guilds are replaced.
+//
+// See GuildScopedCommand for more information.
+func (b *CommandBuilder) Guild(guildID string) *CommandBuilder {
+	b.def.guilds = []string{guildID}
+	return b
+}
+
+// DefaultMemberPermissions sets the permissions a
+// member needs to have by default to use the command.
+// When not set, all members can use the command.
+//
+// See PermissionedCommand for more information.
+func (b *CommandBuilder) DefaultMemberPermissions(permissions int64) *CommandBuilder {
+	b.def.permissions = &permissions
+	return b
+}
+
+// NSFW marks the command as age-restricted.
+//
+// See NSFWCommand for more information.
+func (b *CommandBuilder) NSFW(nsfw bool) *CommandBuilder {
+	b.def.nsfw = nsfw
+	return b
+}
+
+// Contexts sets the interaction contexts in which
+// the command can be used.
+//
+// See ContextAwareCommand for more information.
+func (b *CommandBuilder) Contexts(contexts ...discordgo.InteractionContextType) *CommandBuilder {
+	b.def.contexts = append(b.def.contexts, contexts...)
+	return b
+}
+
+// IntegrationTypes sets the installation contexts in
+// which the command is available.
+//
+// See ContextAwareCommand for more information.
+func (b *CommandBuilder) IntegrationTypes(types ...discordgo.ApplicationIntegrationType) *CommandBuilder {
+	b.def.integrationTypes = append(b.def.integrationTypes, types...)
+	return b
+}
+
+// ResponsePolicy sets the response policy of the
+// command.
+//
+// See ResponsePolicyCommand for more information.
+func (b *CommandBuilder) ResponsePolicy(policy ResponsePolicy) *CommandBuilder {
+	b.def.responsePolicy = policy
+	return b
+}
+
+// Handler sets the function called on command
+// execution and returns the built command.
+//
+// Depending on the constructor of the builder, the
+// returned command implements SlashCommand,
+// UserCommand or MessageCommand. The builder can be
+// reused afterwards without affecting the returned
+// command.
+//
+// It panics if the passed handler is nil.
+func (b *CommandBuilder) Handler(handler func(ctx Context) error) Command {
+	if handler == nil {
+		panic("ken: nil handler for command " + b.def.name)
+	}
+
+	def := b.def.copy()
+	def.run = handler
+	cmd := builtCommand{def: def}
+
+	switch b.typ {
+	case discordgo.UserApplicationCommand:
+		return &builtUserCommand{cmd}
+	case discordgo.MessageApplicationCommand:
+		return &builtMessageCommand{cmd}
+	default:
+		return &builtSlashCommand{cmd}
+	}
+}
+
+type commandDefinition struct {
+	name             string
+	description      string
+	version          string
+	options          []*discordgo.ApplicationCommandOption
+	run              func(ctx Context) error
+	autocomplete     func(ctx *AutocompleteContext) ([]*discordgo.ApplicationCommandOptionChoice, error)
+	dmCapable        bool
+	guilds           []string
+	permissions      *int64
+	nsfw             bool
+	contexts         []discordgo.InteractionContextType
+	integrationTypes []discordgo.ApplicationIntegrationType
+	responsePolicy   ResponsePolicy
+}
+
+func (d commandDefinition) copy() commandDefinition {
+	d.options = append([]*discordgo.ApplicationCommandOption(nil), d.options...)
+	d.guilds = append([]string(nil), d.guilds...)
+	if d.contexts != nil {
+		d.contexts = append([]discordgo.InteractionContextType{}, d.contexts...)
+	}
+	if d.integrationTypes != nil {
+		d.integrationTypes = append([]discordgo.ApplicationIntegrationType{}, d.integrationTypes...)
+	}
+	if d.permissions != nil {
+		perms := *d.permissions
+		d.permissions = &perms
+	}
+	return d
+}
+
+// builtCommand implements the command interfaces
+// shared by all command types built with the
+// CommandBuilder.
+//
+// Optional interfaces of which the properties have
+// not been set are reported as not implemented via
+// optionalCommand.
+type builtCommand struct {
+	def commandDefinition
+}
+
+var (
+	_ Command                 = (*builtCommand)(nil)
+	_ DmCapable               = (*builtCommand)(nil)
+	_ GuildScopedCommand      = (*builtCommand)(nil)
+	_ MultiGuildScopedCommand = (*builtCommand)(nil)
+	_ PermissionedCommand     = (*builtCommand)(nil)
+	_ NSFWCommand             = (*builtCommand)(nil)
+	_ ContextAwareCommand     = (*builtCommand)(nil)
+	_ ResponsePolicyCommand   = (*builtCommand)(nil)
+	_ optionalCommand         = (*builtCommand)(nil)
+)
+
+func (c *builtCommand) Name() string {
+	return c.def.name
+}
+
+func (c *builtCommand) Description() string {
+	return c.def.description
+}
+
+func (c *builtCommand) Run(ctx Context) error {
+	return c.def.run(ctx)
+}
+
+func (c *builtCommand) IsDmCapable() bool {
+	return c.def.dmCapable
+}
+
+func (c *builtCommand) Guild() string {
+	if len(c.def.guilds) == 0 {
+		return ""
+	}
+	return c.def.guilds[0]
+}
+
+func (c *builtCommand) Guilds() []string {
+	return c.def.guilds
+}
+
+func (c *builtCommand) DefaultMemberPermissions() int64 {
+	if c.def.permissions == nil {
+		return 0
+	}
+	return *c.def.permissions
+}
+
+func (c *builtCommand) IsNSFW() bool {
+	return c.def.nsfw
+}
+
+func (c *builtCommand) Contexts() []discordgo.InteractionContextType {
+	return c.def.contexts
+}
+
+func (c *builtCommand) IntegrationTypes() []discordgo.ApplicationIntegrationType {
+	return c.def.integrationTypes
+}
+
+func (c *builtCommand) ResponsePolicy() ResponsePolicy {
+	return c.def.responsePolicy
+}
+
+func (c *builtCommand) implements(iface reflect.Type) bool {
+	switch iface {
+	case typeOf[DmCapable]():
+		return c.def.dmCapable
+	case typeOf[GuildScopedCommand](), typeOf[MultiGuildScopedCommand]():
+		return len(c.def.guilds) != 0
+	case typeOf[PermissionedCommand]():
+		return c.def.permissions != nil
+	case typeOf[NSFWCommand]():
+		return c.def.nsfw
+	case typeOf[ContextAwareCommand]():
+		return c.def.contexts != nil || c.def.integrationTypes != nil
+	case typeOf[ResponsePolicyCommand]():
+		return c.def.responsePolicy != ResponsePolicy{}
+	case typeOf[AutocompleteCommand]():
+		return c.def.autocomplete != nil
+	}
+	return true
+}
+
+type builtSlashCommand struct {
+	builtCommand
+}
+
+var (
+	_ SlashCommand        = (*builtSlashCommand)(nil)
+	_ AutocompleteCommand = (*builtSlashCommand)(nil)
+)
+
+func (c *builtSlashCommand) Version() string {
+	return c.def.version
+}
+
+func (c *builtSlashCommand) Options() []*discordgo.ApplicationCommandOption {
+	return c.def.options
+}
+
+func (c *builtSlashCommand) Autocomplete(ctx *AutocompleteContext) ([]*discordgo.ApplicationCommandOptionChoice, error) {
+	return c.def.autocomplete(ctx)
+}
+
+type builtUserCommand struct {
+	builtCommand
+}
+
+var _ UserCommand = (*builtUserCommand)(nil)
+
+func (c *builtUserCommand) TypeUser() {}
+
+type builtMessageCommand struct {
+	builtCommand
+}
+
+var _ MessageCommand = (*builtMessageCommand)(nil)
+
+func (c *builtMessageCommand) TypeMessage() {}
@@ -0,0 +1,124 @@
+package ken
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCommandBuilderOptionalInterfaces(t *testing.T) {
+	run := func(ctx Context) error { return nil }
+	autocomplete := func(ctx *AutocompleteContext) ([]*discordgo.ApplicationCommandOptionChoice, error) {
+		return nil, nil
+	}
+
+	cases := []struct {
+		name         string
+		cmd          Command
+		typ          discordgo.ApplicationCommandType
+		permissions  bool
+		nsfw         bool
+		autocomplete bool
+	}{
+		{"slash", NewSlash("a").Handler(run), discordgo.ChatApplicationCommand, false, false, false},
+		{"slash with autocomplete", NewSlash("a").Autocomplete(autocomplete).Handler(run),
+			discordgo.ChatApplicationCommand, false, false, true},
+		{"slash with all", NewSlash("a").Autocomplete(autocomplete).DefaultMemberPermissions(0).NSFW(true).Handler(run),
+			discordgo.ChatApplicationCommand, true, true, true},
+		{"slash with permissions", NewSlash("a").DefaultMemberPermissions(0).Handler(run),
+			discordgo.ChatApplicationCommand, true, false, false},
+		{"slash with nsfw reset", NewSlash("a").NSFW(true).NSFW(false).Handler(run),
+			discordgo.ChatApplicationCommand, false, false, false},
+		{"user", NewUser("a").Handler(run), discordgo.UserApplicationCommand, false, false, false},
+		{"user with nsfw", NewUser("a").NSFW(true).Handler(run), discordgo.UserApplicationCommand, false, true, false},
+		{"message", NewMessage("a").Handler(run), discordgo.MessageApplicationCommand, false, false, false},
+		{"message with all", NewMessage("a").DefaultMemberPermissions(8).NSFW(true).Handler(run),
+			discordgo.MessageApplicationCommand, true, true, false},
+	}
+
+	k, err := New(&discordgo.Session{State: discordgo.NewState()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if typ := commandType(c.cmd); typ != c.typ {
+				t.Errorf("expected type %d, got %d", c.typ, typ)
+			}
+			if _, ok := asOptional[PermissionedCommand](c.cmd); ok != c.permissions {
+				t.Errorf("expected PermissionedCommand to be implemented: %v", c.permissions)
+			}
+			if _, ok := asOptional[NSFWCommand](c.cmd); ok != c.nsfw {
+				t.Errorf("expected NSFWCommand to be implemented: %v", c.nsfw)
+			}
+			if _, ok := asOptional[AutocompleteCommand](c.cmd); ok != c.autocomplete {
+				t.Errorf("expected AutocompleteCommand to be implemented: %v", c.autocomplete)
+			}
+
+			acmd := k.toApplicationCommand(c.cmd)
+			if (acmd.DefaultMemberPermissions != nil) != c.permissions {
+				t.Errorf("expected default member permissions to be set: %v", c.permissions)
+			}
+			if (acmd.NSFW != nil) != c.nsfw {
+				t.Errorf("expected NSFW to be set: %v", c.nsfw)
+			}
+		})
+	}
+}
+
+func TestCommandBuilderCommandInfo(t *testing.T) {
+	k, err := New(&discordgo.Session{State: discordgo.NewState()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewSlash("ping").
+		Description("Ping pong").
+		NSFW(true).
+		Handler(func(ctx Context) error { return nil })
+	if err = k.RegisterCommands(cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	cis := k.GetCommandInfo()
+	if len(cis) != 1 {
+		t.Fatalf("expected one command info, got %d", len(cis))
+	}
+	for _, name := range []string{"Name", "Description", "Version", "IsNSFW"} {
+		if _, ok := cis[0].Implementations[name]; !ok {
+			t.Errorf("expected %s to be listed", name)
+		}
+	}
+	for _, name := range []string{"IsDmCapable", "Guild", "Guilds", "DefaultMemberPermissions",
+		"Contexts", "IntegrationTypes", "ResponsePolicy"} {
+		if _, ok := cis[0].Implementations[name]; ok {
+			t.Errorf("expected %s not to be listed", name)
+		}
+	}
+}
+
+func TestCommandBuilderGuild(t *testing.T) {
+	run := func(ctx Context) error { return nil }
+
+	cases := []struct {
+		name string
+		cmd  Command
+		want []string
+	}{
+		{"global", NewSlash("a").Handler(run), []string{""}},
+		{"guild", NewSlash("a").Guild("1").Handler(run), []string{"1"}},
+		{"guild replaces guild", NewSlash("a").Guild("1").Guild("2").Handler(run), []string{"2"}},
+		{"guild replaces guilds", NewSlash("a").Guilds("1", "2").Guild("3").Handler(run), []string{"3"}},
+		{"guilds", NewSlash("a").Guild("1").Guilds("2", "3").Handler(run), []string{"1", "2", "3"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := commandGuilds(c.cmd); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package ken
+
+import (
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken/store"
+)
+
+// loadIdcache loads the registered commands from the
+// CommandStore. The loaded entries are kept until the
+// application ID is known and are then moved into the
+// idcache by adoptIdcache.
+//
+// If the CommandStore does not implement CommandStoreV2,
+// the entries are assembled from the stored IDs.
+func (k *Ken) loadIdcache() (err error) {
+	var entries []*store.CommandEntry
+
+	switch st := k.opt.CommandStore.(type) {
+	case store.CommandStoreV2:
+		k.hashesStored = true
+		entries, err = st.LoadEntries()
+		if err != nil {
+			return
+		}
+	default:
+		var ids map[string]string
+		ids, err = st.Load()
+		if err != nil {
+			return
+		}
+		entries = make([]*store.CommandEntry, 0, len(ids))
+		for key, id := range ids {
+			e := store.ParseKey(key)
+			e.ID = id
+			entries = append(entries, e)
+		}
+	}
+
+	for _, e := range entries {
+		// Entries stored by previous versions do not
+		// contain the command type, so they are assumed
+		// to be slash commands. Wrongly assumed types are
+		// corrected on the next sync.
+		if e.Type == 0 {
+			e.Type = discordgo.ChatApplicationCommand
+		}
+	}
+	k.loadedEntries = entries
+
+	return
+}
+
+// storeIdcache stores the idcache to the CommandStore,
+// if specified.
+func (k *Ken) storeIdcache() {
+	if k.opt.CommandStore == nil {
+		return
+	}
+
+	var err error
+
+	switch st := k.opt.CommandStore.(type) {
+	case store.CommandStoreV2:
+		entries := make([]*store.CommandEntry, 0,
+			len(k.idcache)+len(k.foreignEntries)+len(k.loadedEntries))
+		for _, e := range k.idcache {
+			entries = append(entries, e)
+		}
+		entries = append(entries, k.foreignEntries...)
+		entries = append(entries, k.loadedEntries...)
+		sort.Slice(entries, func(i, j int) bool {
+			a, b := entries[i], entries[j]
+			if a.ApplicationID != b.ApplicationID {
+				return a.ApplicationID < b.ApplicationID
+			}
+			if a.GuildID != b.GuildID {
+				return a.GuildID < b.GuildID
+			}
+			if a.Type != b.Type {
+				return a.Type < b.Type
+			}
+			return a.Name < b.Name
+		})
+		err = st.StoreEntries(entries)
+	default:
+		ids := make(map[string]string, len(k.idcache)+len(k.loadedEntries))
+		for _, e := range k.loadedEntries {
+			ids[e.Key()] = e.ID
+		}
+		for _, e := range k.idcache {
+			ids[e.Key()] = e.ID
+		}
+		err = st.Store(ids)
+	}
+
+	if err != nil {
+		k.opt.OnSystemError("idcache storage", err)
+	}
+}
+
+// adoptIdcache moves the loaded entries which have been
+// registered for appID into the idcache. Entries of other
+// applications, as well as entries of the idcache which
+// do not belong to appID, are kept aside so that they are
+// neither synchronized nor lost on the next store.
+func (k *Ken) adoptIdcache(appID string) {
+	idcache, foreign := k.adoptedIdcache(appID)
+	k.idcache = idcache
+	k.foreignEntries = append(k.foreignEntries, foreign...)
+	k.loadedEntries = nil
+}
+
+// adoptedIdcache returns the idcache as it would be after
+// adopting the loaded entries for appID as well as the
+// entries which do not belong to appID without changing
+// the state of Ken.
+//
+// Entries without application ID have been stored by
+// previous versions and are assumed to belong to appID,
+// unless an entry of appID exists for the same command.
+func (k *Ken) adoptedIdcache(appID string) (
+	idcache map[commandKey]*store.CommandEntry,
+	foreign []*store.CommandEntry,
+) {
+	idcache = make(map[commandKey]*store.CommandEntry, len(k.idcache)+len(k.loadedEntries))
+	for key, e := range k.idcache {
+		if e.ApplicationID != "" && e.ApplicationID != appID {
+			foreign = append(foreign, e)
+			continue
+		}
+		idcache[key] = e
+	}
+
+	var own, legacy []*store.CommandEntry
+	for _, e := range k.loadedEntries {
+		switch e.ApplicationID {
+		case appID:
+			own = append(own, e)
+		case "":
+			legacy = append(legacy, e)
+		default:
+			foreign = append(foreign, e)
+		}
+	}
+	for _, e := range legacy {
+		idcache[newCommandKey(e.Type, e.GuildID, e.Name)] = e
+	}
+	for _, e := range own {
+		idcache[newCommandKey(e.Type, e.GuildID, e.Name)] = e
+	}
+
+	return
+}
+
+// cacheCommand sets the entry of the application command
+// in the given scope to the idcache.
+//
+// If hash is empty, the command is compared with the
+// registered application command on the next sync.
+func (k *Ken) cacheCommand(
+	appID, guildID string,
+	acmd *discordgo.ApplicationCommand,
+	id, hash string,
+) {
+	k.idcache[applicationCommandKey(guildID, acmd)] = &store.CommandEntry{
+		ID:            id,
+		Name:          acmd.Name,
+		GuildID:       guildID,
+		Type:          acmd.Type,
+		ApplicationID: appID,
+		Hash:          hash,
+	}
+}
+
+// commandKey identifies a command by its type, the
+// guild it is registered on and its name. Discord allows
+// commands of different types as well as commands in
+// different scopes to share the same name.
+type commandKey struct {
+	Type    discordgo.ApplicationCommandType
+	GuildID string
+	Name    string
+}
+
+func newCommandKey(typ discordgo.ApplicationCommandType, guildID, name string) commandKey {
+	if typ == 0 {
+		typ = discordgo.ChatApplicationCommand
+	}
+	return commandKey{
+		Type:    typ,
+		GuildID: guildID,
+		Name:    name,
+	}
+}
+
+func applicationCommandKey(guildID string, acmd *discordgo.ApplicationCommand) commandKey {
+	return newCommandKey(acmd.Type, guildID, acmd.Name)
+}
@@ -0,0 +1,69 @@
+package ken
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken/store"
+)
+
+func TestIdcacheKeepsEntriesOfOtherApplications(t *testing.T) {
+	st := store.NewLocalCommandStore(filepath.Join(t.TempDir(), "commands.json"))
+	err := st.StoreEntries([]*store.CommandEntry{
+		{ID: "1", Name: "ping", Type: discordgo.ChatApplicationCommand, ApplicationID: "staging"},
+		{ID: "2", Name: "ping", Type: discordgo.ChatApplicationCommand, ApplicationID: "prod"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := New(&discordgo.Session{State: discordgo.NewState()}, WithStore(st))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k.adoptIdcache("prod")
+	e := k.idcache[newCommandKey(discordgo.ChatApplicationCommand, "", "ping")]
+	if e == nil || e.ID != "2" {
+		t.Fatalf("expected prod entry in idcache, got %+v", e)
+	}
+
+	k.storeIdcache()
+
+	entries, err := st.LoadEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]string{}
+	for _, e := range entries {
+		ids[e.ApplicationID] = e.ID
+	}
+	if len(entries) != 2 || ids["staging"] != "1" || ids["prod"] != "2" {
+		t.Fatalf("expected entries of both applications, got %v", ids)
+	}
+}
+
+func TestIdcacheStoresEntriesBeforeAdoption(t *testing.T) {
+	st := store.NewLocalCommandStore(filepath.Join(t.TempDir(), "commands.json"))
+	err := st.StoreEntries([]*store.CommandEntry{
+		{ID: "1", Name: "ping", Type: discordgo.ChatApplicationCommand, ApplicationID: "prod"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := New(&discordgo.Session{State: discordgo.NewState()}, WithStore(st))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.storeIdcache()
+
+	entries, err := st.LoadEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ID != "1" {
+		t.Fatalf("expected loaded entry to be kept, got %+v", entries)
+	}
+}
@@ -196,7 +196,7 @@ func (t *ComponentBuilder) build() (unreg func() error, err error) {
 				}
 
 				t.components = []discordgo.MessageComponent{}
-				t.ch.ken.s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+				ctx.GetSession().ChannelMessageEditComplex(&discordgo.MessageEdit{
 					ID:         t.msgId,
 					Channel:    t.chanId,
 					Components: &t.components,
@@ -216,7 +216,7 @@ func (t *ComponentBuilder) build() (unreg func() error, err error) {
 				}
 
 				t.components = removeComponentRecursive(t.components, k)
-				t.ch.ken.s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+				ctx.GetSession().ChannelMessageEditComplex(&discordgo.MessageEdit{
 					ID:         t.msgId,
 					Channel:    t.chanId,
 					Components: &t.components,
@@ -1,6 +1,7 @@
 package ken
 
 import (
+	"context"
 	"sync"
 
 	"github.com/bwmarrin/discordgo"
@@ -22,8 +23,8 @@ type ModalHandlerFunc func(ctx ModalContext) bool
 // callbacks to be executed when a given component has
 // been interacted with.
 type ComponentHandler struct {
-	ken            *Ken
-	unregisterFunc func()
+	ken             *Ken
+	unregisterFuncs []func()
 
 	mtx           sync.RWMutex
 	handlers      map[string]ComponentHandlerFunc
@@ -42,7 +43,9 @@ func NewComponentHandler(ken *Ken) *ComponentHandler {
 	t.ken = ken
 	t.handlers = make(map[string]ComponentHandlerFunc)
 	t.modalHandlers = make(map[string]ModalHandlerFunc)
-	t.unregisterFunc = t.ken.s.AddHandler(t.handle)
+	for _, s := range t.ken.sessions {
+		t.unregisterFuncs = append(t.unregisterFuncs, s.AddHandler(t.handle))
+	}
 	t.ctxPool = sync.Pool{
 		New: func() interface{} {
 			return &componentCtx{}
@@ -115,9 +118,11 @@ func (t *ComponentHandler) AppendToMessage(
 }
 
 // UnregisterDiscordHandler removes the Discord event handler
-// function from the internal DiscordGo Session.
+// function from the internal DiscordGo Sessions.
 func (t *ComponentHandler) UnregisterDiscordHandler() {
-	t.unregisterFunc()
+	for _, unregister := range t.unregisterFuncs {
+		unregister()
+	}
 }
 
 func (t *ComponentHandler) registerModalHandler(customId string, handler ModalHandlerFunc) func() {
@@ -147,16 +152,21 @@ func (t *ComponentHandler) unregisterModalhandler(customId ...string) {
 	}
 }
 
-func (t *ComponentHandler) handle(_ *discordgo.Session, e *discordgo.InteractionCreate) {
+func (t *ComponentHandler) handle(s *discordgo.Session, e *discordgo.InteractionCreate) {
+	if !t.ken.acquireInteraction() {
+		return
+	}
+	defer t.ken.releaseInteraction()
+
 	switch e.Type {
 	case discordgo.InteractionMessageComponent:
-		t.handleMessageComponent(e)
+		t.handleMessageComponent(s, e)
 	case discordgo.InteractionModalSubmit:
-		t.handleModalSubmit(e)
+		t.handleModalSubmit(s, e)
 	}
 }
 
-func (t *ComponentHandler) handleMessageComponent(e *discordgo.InteractionCreate) {
+func (t *ComponentHandler) handleMessageComponent(s *discordgo.Session, e *discordgo.InteractionCreate) {
 	data := e.MessageComponentData()
 
 	t.mtx.RLock()
@@ -171,18 +181,26 @@ func (t *ComponentHandler) handleMessageComponent(e *discordgo.InteractionCreate
 	ctx.Data = data
 	ctx.ephemeral = false
 	ctx.event = e
-	ctx.session = t.ken.s
+	ctx.session = s
 	ctx.ken = t.ken
 	ctx.responded = false
+	ctx.deferred = false
+
+	var cancel context.CancelFunc
+	ctx.ctx, cancel = t.ken.interactionContext(e.Interaction, interactionTokenLifetime)
 
 	defer func() {
+		cancel()
 		t.ctxPool.Put(ctx)
 	}()
 
+	defer ctx.attachHTTPResponder()()
+	defer t.ken.recoverPanic("component", e.Interaction, &ctx.ctxResponder)
+
 	handler(ctx)
 }
 
-func (t *ComponentHandler) handleModalSubmit(e *discordgo.InteractionCreate) {
+func (t *ComponentHandler) handleModalSubmit(s *discordgo.Session, e *discordgo.InteractionCreate) {
 	data := e.ModalSubmitData()
 
 	t.mtx.RLock()
@@ -197,13 +215,21 @@ func (t *ComponentHandler) handleModalSubmit(e *discordgo.InteractionCreate) {
 	ctx.Data = data
 	ctx.ephemeral = false
 	ctx.event = e
-	ctx.session = t.ken.s
+	ctx.session = s
 	ctx.ken = t.ken
 	ctx.responded = false
+	ctx.deferred = false
+
+	var cancel context.CancelFunc
+	ctx.ctx, cancel = t.ken.interactionContext(e.Interaction, interactionTokenLifetime)
 
 	defer func() {
+		cancel()
 		t.modalCtxPool.Put(ctx)
 	}()
 
+	defer ctx.attachHTTPResponder()()
+	defer t.ken.recoverPanic("modal", e.Interaction, &ctx.ctxResponder)
+
 	handler(ctx)
 }
@@ -1,11 +1,26 @@
 package ken
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/rs/xid"
 	"github.com/zekrotja/safepool"
 )
 
+const (
+	// interactionTokenLifetime is the duration after the
+	// creation of an interaction in which responses and
+	// follow up messages can be sent.
+	interactionTokenLifetime = 15 * time.Minute
+	// interactionResponseWindow is the duration after the
+	// creation of an interaction in which the initial
+	// response must be sent.
+	interactionResponseWindow = 3 * time.Second
+)
+
 // ContextResponder defines the implementation of an
 // interaction context with functionalities to respond
 // to the interaction, to set the ephemeral state and
@@ -83,6 +98,31 @@ type ContextResponder interface {
 	// User returns the User object of the executor either from
 	// the events User object or from the events Member object.
 	User() (u *discordgo.User)
+
+	// T returns the message by key from the MessageCatalog
+	// specified in Ken's Options in the locale of the user
+	// who created the interaction. When no message exists
+	// for the users locale, the guilds locale and finally
+	// the DefaultLocale is used as fallback.
+	//
+	// If args are passed, they are used to format the message.
+	T(key string, args ...interface{}) string
+
+	// Context returns the context.Context of the interaction.
+	//
+	// It is derived from the root context of Ken, which is
+	// canceled on Shutdown, and its deadline is set to the
+	// expiry of the interaction token. It is canceled when
+	// the handler has returned.
+	Context() context.Context
+
+	// SetContext replaces the context.Context of the
+	// interaction. This can be used by middlewares to
+	// attach values or timeouts to the context.
+	//
+	// The passed context should be derived from the
+	// current Context.
+	SetContext(ctx context.Context)
 }
 
 // Context defines the implementation of an interaction
@@ -104,6 +144,14 @@ type Context interface {
 	// with additional functionality methods.
 	Options() CommandOptions
 
+	// Bind sets the values of the command options to the
+	// tagged fields of the struct v points to. Users, roles
+	// and channels are taken from the resolved data of the
+	// interaction.
+	//
+	// See OptionsFromStruct for the supported struct tags.
+	Bind(v interface{}) (err error)
+
 	// SlashCommand returns the contexts Command as a
 	// SlashCommand interface.
 	SlashCommand() (cmd SlashCommand, ok bool)
@@ -139,16 +187,26 @@ type Context interface {
 // ctxResponder provides functionailities to respond
 // to an interaction.
 type ctxResponder struct {
+	mtx       sync.Mutex
 	responded bool
+	deferred  bool
 	ken       *Ken
 	session   *discordgo.Session
 	event     *discordgo.InteractionCreate
 	ephemeral bool
+	ctx       context.Context
 }
 
 var _ ContextResponder = (*ctxResponder)(nil)
 
 func (c *ctxResponder) Respond(r *discordgo.InteractionResponse) (err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.respond(r)
+}
+
+func (c *ctxResponder) respond(r *discordgo.InteractionResponse) (err error) {
 	if r.Data == nil {
 		r.Data = new(discordgo.InteractionResponseData)
 	}
@@ -157,6 +215,12 @@ func (c *ctxResponder) Respond(r *discordgo.InteractionResponse) (err error) {
 		if r == nil || r.Data == nil {
 			return
 		}
+		// The interaction has already been acknowledged,
+		// for example by an automatic defer, so there is
+		// nothing left to defer.
+		if isDeferredResponseType(r.Type) {
+			return
+		}
 		_, err = c.GetSession().InteractionResponseEdit(c.event.Interaction, &discordgo.WebhookEdit{
 			Content:         &r.Data.Content,
 			Embeds:          &r.Data.Embeds,
@@ -164,9 +228,18 @@ func (c *ctxResponder) Respond(r *discordgo.InteractionResponse) (err error) {
 			Files:           r.Data.Files,
 			AllowedMentions: r.Data.AllowedMentions,
 		})
+		if err == nil {
+			c.deferred = false
+		}
 	} else {
-		err = c.GetSession().InteractionRespond(c.event.Interaction, r)
+		err = c.ken.interactionRespond(c.GetSession(), c.event.Interaction, r)
+		if err == errInteractionDeferred {
+			c.responded = true
+			c.deferred = true
+			return c.respond(r)
+		}
 		c.responded = err == nil
+		c.deferred = c.responded && isDeferredResponseType(r.Type)
 	}
 	return
 }
@@ -206,6 +279,7 @@ func (c *ctxResponder) FollowUp(wait bool, data *discordgo.WebhookParams) (fumb
 	data.Flags = c.messageFlags(data.Flags)
 	return &FollowUpMessageBuilder{
 		ken:  c.ken,
+		s:    c.session,
 		i:    c.event.Interaction,
 		data: data,
 		wait: wait,
@@ -245,10 +319,14 @@ func (c *ctxResponder) Defer() (err error) {
 }
 
 func (c *ctxResponder) GetEphemeral() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
 	return c.ephemeral
 }
 
 func (c *ctxResponder) SetEphemeral(v bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
 	c.ephemeral = v
 }
 
@@ -260,6 +338,65 @@ func (c *ctxResponder) GetEvent() *discordgo.InteractionCreate {
 	return c.event
 }
 
+// startAutoDefer responds to the interaction with a
+// deferred response after d has passed when it has not
+// been responded to until then.
+//
+// The returned function stops the timer and waits for a
+// running automatic defer to finish. It must be called
+// before the context is reused.
+func (c *ctxResponder) startAutoDefer(d time.Duration) (stop func()) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	t := time.AfterFunc(d, func() {
+		defer wg.Done()
+		c.autoDefer()
+	})
+	return func() {
+		if t.Stop() {
+			wg.Done()
+		}
+		wg.Wait()
+	}
+}
+
+func (c *ctxResponder) autoDefer() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.responded {
+		return
+	}
+
+	typ, ok := deferredResponseType(c.event.Type)
+	if !ok {
+		return
+	}
+	err := c.respond(&discordgo.InteractionResponse{
+		Type: typ,
+	})
+	if err != nil {
+		c.ken.opt.OnSystemError("auto defer", err)
+	}
+}
+
+// attachHTTPResponder attaches the context to the
+// httpResponder of its interaction, if it has been
+// received via the InteractionsHandler, so that an
+// automatic defer is sent through the context.
+//
+// The returned function detaches the context again. It
+// must be called before the context is reused.
+func (c *ctxResponder) attachHTTPResponder() (detach func()) {
+	v, ok := c.ken.httpResponders.Load(c.event.ID)
+	if !ok {
+		return func() {}
+	}
+	res := v.(*httpResponder)
+	res.attach(c)
+	return res.detach
+}
+
 func (c *ctxResponder) messageFlags(p discordgo.MessageFlags) (f discordgo.MessageFlags) {
 	f = p
 	if c.ephemeral {
@@ -278,6 +415,18 @@ func (c *ctxResponder) User() (u *discordgo.User) {
 	return
 }
 
+func (c *ctxResponder) T(key string, args ...interface{}) string {
+	return c.ken.T(interactionLocales(c.event.Interaction), key, args...)
+}
+
+func (c *ctxResponder) Context() context.Context {
+	return c.ctx
+}
+
+func (c *ctxResponder) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
 // Ctx holds the invokation context of
 // a command.
 //
@@ -329,6 +478,12 @@ func (c *Ctx) Options() CommandOptions {
 	return c.event.ApplicationCommandData().Options
 }
 
+// Bind sets the values of the command options to the
+// tagged fields of the struct v points to.
+func (c *Ctx) Bind(v interface{}) (err error) {
+	return c.Options().bind(c, v)
+}
+
 // SlashCommand returns the contexts Command as a
 // SlashCommand interface.
 func (c *Ctx) SlashCommand() (cmd SlashCommand, ok bool) {
@@ -351,6 +506,7 @@ func (c *Ctx) MessageCommand() (cmd MessageCommand, ok bool) {
 }
 
 func (c *Ctx) ResetState() {
+	c.ctx = nil
 	c.Purge()
 }
 
@@ -413,12 +569,23 @@ type SubCommandContext interface {
 	// GetSubCommandName returns the sub command
 	// name which has been invoked.
 	GetSubCommandName() string
+
+	// GetSubCommandPath returns the names of the
+	// invoked sub command group and sub command
+	// separated by a space, for example
+	// "config set".
+	GetSubCommandPath() string
 }
 
+// parentContext is used to embed the parent Context into
+// subCommandCtx without shadowing its Context method.
+type parentContext = Context
+
 type subCommandCtx struct {
-	Context
+	parentContext
 
 	subCommandName string
+	subCommandPath string
 }
 
 var _ SubCommandContext = (*subCommandCtx)(nil)
@@ -426,13 +593,34 @@ var _ SubCommandContext = (*subCommandCtx)(nil)
 // Options returns the options array of the called
 // sub command.
 func (c *subCommandCtx) Options() CommandOptions {
-	return c.Context.Options().GetByName(c.subCommandName).Options
+	return c.parentContext.Options().GetByName(c.subCommandName).Options
+}
+
+// Bind sets the values of the options of the called
+// sub command to the tagged fields of the struct v
+// points to.
+func (c *subCommandCtx) Bind(v interface{}) (err error) {
+	return c.Options().bind(c, v)
 }
 
 func (c *subCommandCtx) GetSubCommandName() string {
 	return c.subCommandName
 }
 
+func (c *subCommandCtx) GetSubCommandPath() string {
+	return c.subCommandPath
+}
+
+// ResetState only resets the sub command context
+// itself, so that the parent Context is not reset
+// when the sub command context is put back into
+// the pool.
+func (c *subCommandCtx) ResetState() {
+	c.parentContext = nil
+	c.subCommandName = ""
+	c.subCommandPath = ""
+}
+
 func (c *subCommandCtx) HandleSubCommands(handler ...CommandHandler) (err error) {
 	return handleSubCommands(c, handler)
 }
@@ -561,31 +749,44 @@ type modalCtx struct {
 
 var _ ModalContext = (*modalCtx)(nil)
 
-func (c modalCtx) GetData() discordgo.ModalSubmitInteractionData {
+func (c *modalCtx) GetData() discordgo.ModalSubmitInteractionData {
 	return c.Data
 }
 
-func (c modalCtx) GetComponentByID(customId string) MessageComponent {
+func (c *modalCtx) GetComponentByID(customId string) MessageComponent {
 	return MessageComponent{getComponentByID(customId, c.GetData().Components)}
 }
 
 // -------------------------------------------------------------------------------------------------
 
 func handleSubCommands(c Context, handler []CommandHandler) (err error) {
-	opt := c.Options().Get(0)
+	opt := findSubCommandOption(c.Options())
+	if opt == nil {
+		return nil
+	}
 	for _, h := range handler {
 		if opt.Type != h.Type() || opt.Name != h.OptionName() {
 			continue
 		}
+		return runSubCommand(c, opt.Name, h.RunHandler)
+	}
+	return nil
+}
 
-		ctx := c.GetKen().subCtxPool.Get()
-		ctx.Context = c
-		ctx.subCommandName = h.OptionName()
-		err = h.RunHandler(ctx)
-		c.GetKen().subCtxPool.Put(ctx)
-		break
+// runSubCommand runs handler with a sub command context
+// scoped to the sub command with the given name.
+func runSubCommand(c Context, name string, handler func(ctx SubCommandContext) error) error {
+	path := name
+	if sc, ok := c.(SubCommandContext); ok {
+		path = sc.GetSubCommandPath() + " " + name
 	}
-	return err
+
+	ctx := c.GetKen().subCtxPool.Get()
+	defer c.GetKen().subCtxPool.Put(ctx)
+	ctx.parentContext = c
+	ctx.subCommandName = name
+	ctx.subCommandPath = path
+	return handler(ctx)
 }
 
 func getComponentByID(
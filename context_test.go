@@ -0,0 +1,55 @@
+package ken
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestHandleSubCommandsKeepsParentState(t *testing.T) {
+	k, err := New(&discordgo.Session{State: discordgo.NewState()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := k.ctxPool.Get()
+	ctx.ken = k
+	ctx.event = &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionApplicationCommand,
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name: "cmd",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: "sub", Type: discordgo.ApplicationCommandOptionSubCommand},
+			},
+		},
+	}}
+	var cancel func()
+	ctx.ctx, cancel = k.interactionContext(ctx.event.Interaction, interactionTokenLifetime)
+	defer cancel()
+	ctx.Set("key", "value")
+
+	var called bool
+	err = ctx.HandleSubCommands(SubCommandHandler{
+		Name: "sub",
+		Run: func(sctx SubCommandContext) error {
+			called = true
+			if sctx.Context() == nil {
+				t.Error("expected sub command context to have a context")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Fatal("expected sub command handler to be called")
+	}
+
+	if ctx.Context() == nil {
+		t.Error("expected parent context to be kept after sub command")
+	}
+	if ctx.Get("key") != "value" {
+		t.Error("expected parent objects to be kept after sub command")
+	}
+}
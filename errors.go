@@ -5,6 +5,16 @@ import "errors"
 var (
 	ErrEmptyCommandName         = errors.New("command name can not be empty")
 	ErrCommandAlreadyRegistered = errors.New("command with the same name has already been rgistered")
+	ErrCommandNotFound          = errors.New("command with the given name has not been registered")
 	ErrInvalidMiddleware        = errors.New("the instance must implement MiddlewareBefore, MiddlewareAfter or both")
+	ErrNoSessions               = errors.New("at least one session must be passed")
 	ErrNotDMCapable             = errors.New("The executed command is not able to be executed in DMs")
+	ErrInvalidOption            = errors.New("invalid option")
+	ErrInvalidBindTarget        = errors.New("invalid option struct")
+	ErrOptionNotFound           = errors.New("option has not been found")
+	ErrOptionTypeMismatch       = errors.New("option is not of the requested type")
+	ErrOptionNotResolved        = errors.New("option value has not been resolved")
+	ErrAttachmentTooLarge       = errors.New("attachment exceeds the maximum size")
+	ErrAttachmentContentType    = errors.New("attachment content type is not allowed")
+	ErrSubCommandNotFound       = errors.New("no handler has been registered for the invoked sub command")
 )
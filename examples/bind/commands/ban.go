@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken"
+)
+
+type BanArgs struct {
+	User   *discordgo.User `ken:"user,required" description:"The user to be banned."`
+	Reason string          `ken:"reason,required" description:"The reason of the ban." min:"3" max:"200"`
+	Days   int             `ken:"days" description:"Days of messages to be deleted." min:"0" max:"7"`
+}
+
+type BanCommand struct{}
+
+var (
+	_ ken.SlashCommand = (*BanCommand)(nil)
+)
+
+func (c *BanCommand) Name() string {
+	return "ban"
+}
+
+func (c *BanCommand) Description() string {
+	return "Ban a user (not really)."
+}
+
+func (c *BanCommand) Version() string {
+	return "1.0.0"
+}
+
+func (c *BanCommand) Type() discordgo.ApplicationCommandType {
+	return discordgo.ChatApplicationCommand
+}
+
+func (c *BanCommand) Options() []*discordgo.ApplicationCommandOption {
+	return ken.MustOptionsFromStruct(BanArgs{})
+}
+
+func (c *BanCommand) Run(ctx ken.Context) (err error) {
+	var args BanArgs
+	if err = ctx.Bind(&args); err != nil {
+		return
+	}
+
+	err = ctx.RespondMessage(fmt.Sprintf(
+		"%s would have been banned for `%s` deleting messages of the last %d days.",
+		args.User.Mention(), args.Reason, args.Days))
+	return
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken"
+	"github.com/zekrotja/ken/examples/bind/commands"
+	"github.com/zekrotja/ken/store"
+)
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	token := os.Getenv("TOKEN")
+
+	session, err := discordgo.New("Bot " + token)
+	if err != nil {
+		panic(err)
+	}
+	defer session.Close()
+
+	k, err := ken.New(session, ken.Options{
+		CommandStore: store.NewDefault(),
+	})
+	must(err)
+
+	must(k.RegisterCommands(
+		new(commands.BanCommand),
+	))
+
+	defer k.Unregister()
+
+	must(session.Open())
+
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt, os.Kill)
+	<-sc
+}
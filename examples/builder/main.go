@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken"
+	"github.com/zekrotja/ken/store"
+)
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	token := os.Getenv("TOKEN")
+
+	session, err := discordgo.New("Bot " + token)
+	if err != nil {
+		panic(err)
+	}
+	defer session.Close()
+
+	k, err := ken.New(session, ken.Options{
+		CommandStore: store.NewDefault(),
+	})
+	must(err)
+
+	must(k.RegisterCommands(
+		ken.NewSlash("ping").
+			Description("Ping pong").
+			DmCapable(true).
+			Handler(func(ctx ken.Context) error {
+				return ctx.RespondMessage("Pong!")
+			}),
+
+		ken.NewSlash("echo").
+			Description("Echoes the given message").
+			Option(&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "message",
+				Description: "The message to echo",
+				Required:    true,
+			}).
+			ResponsePolicy(ken.ResponsePolicy{Ephemeral: true}).
+			Handler(func(ctx ken.Context) error {
+				return ctx.RespondMessage(ctx.Options().StringOr("message", ""))
+			}),
+
+		ken.NewUser("info").
+			DefaultMemberPermissions(discordgo.PermissionManageMessages).
+			Handler(func(ctx ken.Context) error {
+				user := ctx.GetEvent().ApplicationCommandData().TargetID
+				return ctx.RespondMessage(fmt.Sprintf("<@%s>", user))
+			}),
+	))
+
+	defer k.Unregister()
+
+	must(session.Open())
+
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt, os.Kill)
+	<-sc
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"net/http"
+	"os"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken"
+	"github.com/zekrotja/ken/examples/basic/commands"
+)
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	token := os.Getenv("TOKEN")
+
+	publicKey, err := hex.DecodeString(os.Getenv("PUBLIC_KEY"))
+	must(err)
+
+	// The session is only used to send requests to the
+	// REST API, so it does not need to be opened.
+	session, err := discordgo.New("Bot " + token)
+	must(err)
+
+	k, err := ken.New(session)
+	must(err)
+
+	must(k.RegisterCommands(new(commands.TestCommand)))
+
+	// Commands are usually synchronized when the Ready event
+	// is received, which never happens without a gateway
+	// connection.
+	must(k.Sync())
+
+	http.Handle("/interactions", ken.NewInteractionsHandler(k, ed25519.PublicKey(publicKey)))
+	must(http.ListenAndServe(":8080", nil))
+}
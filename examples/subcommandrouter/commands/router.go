@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken"
+)
+
+type RouterCommand struct {
+	router *ken.SubCommandRouter
+}
+
+var (
+	_ ken.SlashCommand = (*RouterCommand)(nil)
+	_ ken.DmCapable    = (*RouterCommand)(nil)
+)
+
+func NewRouterCommand() *RouterCommand {
+	c := &RouterCommand{}
+	c.router = ken.NewSubCommandRouter().
+		SubCommand("ping", "Ping pong", c.ping).
+		Group("group", "Some sub command group", func(g *ken.SubCommandGroupRouter) {
+			g.SubCommand("one", "First sub command", c.one,
+				&discordgo.ApplicationCommandOption{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "arg",
+					Description: "Argument",
+					Required:    true,
+				})
+			g.SubCommand("two", "Second sub command", c.two,
+				&discordgo.ApplicationCommandOption{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "arg",
+					Description: "Argument",
+				})
+		})
+	return c
+}
+
+func (c *RouterCommand) Name() string {
+	return "router"
+}
+
+func (c *RouterCommand) Description() string {
+	return "An example command using a sub command router."
+}
+
+func (c *RouterCommand) Version() string {
+	return "1.0.0"
+}
+
+func (c *RouterCommand) Type() discordgo.ApplicationCommandType {
+	return discordgo.ChatApplicationCommand
+}
+
+func (c *RouterCommand) Options() []*discordgo.ApplicationCommandOption {
+	return c.router.Options()
+}
+
+func (c *RouterCommand) IsDmCapable() bool {
+	return true
+}
+
+func (c *RouterCommand) Run(ctx ken.Context) (err error) {
+	return c.router.Run(ctx)
+}
+
+func (c *RouterCommand) ping(ctx ken.SubCommandContext) (err error) {
+	return ctx.RespondMessage("pong")
+}
+
+func (c *RouterCommand) one(ctx ken.SubCommandContext) (err error) {
+	arg := ctx.Options().GetByName("arg").StringValue()
+	return ctx.RespondMessage(fmt.Sprintf("%s: %s", ctx.GetSubCommandPath(), arg))
+}
+
+func (c *RouterCommand) two(ctx ken.SubCommandContext) (err error) {
+	arg := ctx.Options().IntOr("arg", 0)
+	return ctx.RespondMessage(fmt.Sprintf("%s: %d", ctx.GetSubCommandPath(), arg))
+}
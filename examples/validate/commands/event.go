@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken"
+	"github.com/zekrotja/ken/middlewares/validate"
+)
+
+const dateLayout = "2006-01-02"
+
+var minDate = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type EventCommand struct{}
+
+var (
+	_ ken.SlashCommand          = (*EventCommand)(nil)
+	_ validate.ValidatedCommand = (*EventCommand)(nil)
+)
+
+var eventConstraints = validate.Constraints{
+	Rules: map[string][]validate.Rule{
+		"create name":  {validate.Regex(`^[\w\- ]{3,32}$`)},
+		"create start": {validate.DateRange(dateLayout, minDate, time.Time{})},
+		"create end":   {validate.DateRange(dateLayout, minDate, time.Time{})},
+		"create link":  {validate.URLHosts("*.example.com", "example.com")},
+	},
+	Checks: []validate.Check{
+		validate.DateOrder(dateLayout, "create start", "create end"),
+	},
+}
+
+func (c *EventCommand) Name() string {
+	return "event"
+}
+
+func (c *EventCommand) Description() string {
+	return "Manage events"
+}
+
+func (c *EventCommand) Version() string {
+	return "1.0.0"
+}
+
+func (c *EventCommand) Type() discordgo.ApplicationCommandType {
+	return discordgo.ChatApplicationCommand
+}
+
+func (c *EventCommand) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "create",
+			Description: "Create a new event",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "name",
+					Description: "The name of the event",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "start",
+					Description: "The start date (YYYY-MM-DD)",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "end",
+					Description: "The end date (YYYY-MM-DD)",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "link",
+					Description: "A link to the event page",
+				},
+			},
+		},
+	}
+}
+
+func (c *EventCommand) Constraints() validate.Constraints {
+	return eventConstraints
+}
+
+func (c *EventCommand) Run(ctx ken.Context) (err error) {
+	err = ctx.HandleSubCommands(
+		ken.SubCommandHandler{Name: "create", Run: c.create},
+	)
+	return
+}
+
+func (c *EventCommand) create(ctx ken.SubCommandContext) (err error) {
+	opts := ctx.Options()
+	err = ctx.RespondMessage(fmt.Sprintf("Event **%s** created from %s to %s.",
+		opts.GetByName("name").StringValue(),
+		opts.GetByName("start").StringValue(),
+		opts.GetByName("end").StringValue()))
+	return
+}
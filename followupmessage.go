@@ -10,6 +10,7 @@ import (
 // message interaction response.
 type FollowUpMessageBuilder struct {
 	ken *Ken
+	s   *discordgo.Session
 	i   *discordgo.Interaction
 
 	data *discordgo.WebhookParams
@@ -27,9 +28,10 @@ func (b *FollowUpMessageBuilder) Send() *FollowUpMessage {
 
 	fum := &FollowUpMessage{
 		ken: b.ken,
+		s:   b.s,
 		i:   b.i,
 	}
-	fum.Message, fum.Error = b.ken.s.FollowupMessageCreate(b.i, b.wait, b.data)
+	fum.Message, fum.Error = b.s.FollowupMessageCreate(b.i, b.wait, b.data)
 	if fum.HasError() {
 		return fum
 	}
@@ -65,6 +67,7 @@ type FollowUpMessage struct {
 	Error error
 
 	ken *Ken
+	s   *discordgo.Session
 	i   *discordgo.Interaction
 
 	unregisterComponentHandlers func() error
@@ -78,7 +81,7 @@ func (m *FollowUpMessage) Edit(data *discordgo.WebhookEdit) (err error) {
 		return
 	}
 
-	inter, err := m.ken.s.FollowupMessageEdit(m.i, m.ID, data)
+	inter, err := m.s.FollowupMessageEdit(m.i, m.ID, data)
 	if err != nil {
 		return
 	}
@@ -103,7 +106,7 @@ func (m *FollowUpMessage) Delete() (err error) {
 		return
 	}
 
-	err = m.ken.s.FollowupMessageDelete(m.i, m.ID)
+	err = m.s.FollowupMessageDelete(m.i, m.ID)
 	return
 }
 
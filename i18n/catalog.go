@@ -0,0 +1,234 @@
+// Package i18n provides a simple translation catalog
+// which can be used to localize commands and messages.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// UnmarshalFunc decodes the passed data into v.
+//
+// This can be used to load translation files in other
+// formats than JSON, for example by passing yaml.Unmarshal.
+type UnmarshalFunc func(data []byte, v interface{}) error
+
+// Catalog holds translated messages by locale and
+// message key.
+type Catalog struct {
+	mtx      sync.RWMutex
+	messages map[discordgo.Locale]map[string]string
+}
+
+// NewCatalog returns a new empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		messages: make(map[discordgo.Locale]map[string]string),
+	}
+}
+
+// Set sets the message for the given locale and key.
+func (c *Catalog) Set(locale discordgo.Locale, key, message string) *Catalog {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.set(locale, key, message)
+	return c
+}
+
+// SetAll sets all passed messages by key for the
+// given locale.
+func (c *Catalog) SetAll(locale discordgo.Locale, messages map[string]string) *Catalog {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for key, message := range messages {
+		c.set(locale, key, message)
+	}
+	return c
+}
+
+// Get returns the message for the given locale and
+// key. If no message could be found, ok is false.
+func (c *Catalog) Get(locale discordgo.Locale, key string) (message string, ok bool) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	message, ok = c.messages[locale][key]
+	return
+}
+
+// Locales returns all locales for which the catalog
+// contains messages in ascending order.
+func (c *Catalog) Locales() (locales []discordgo.Locale) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	locales = make([]discordgo.Locale, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Slice(locales, func(i, j int) bool {
+		return locales[i] < locales[j]
+	})
+	return
+}
+
+// Localizations returns all messages of the given key
+// mapped by their locale. If no message exists for the
+// key, nil is returned.
+func (c *Catalog) Localizations(key string) (localizations map[discordgo.Locale]string) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	for locale, messages := range c.messages {
+		message, ok := messages[key]
+		if !ok {
+			continue
+		}
+		if localizations == nil {
+			localizations = make(map[discordgo.Locale]string)
+		}
+		localizations[locale] = message
+	}
+	return
+}
+
+// Load decodes the messages for the given locale from
+// the passed reader using the given unmarshal function.
+// If no unmarshal function is passed, the data is
+// decoded as JSON.
+//
+// The data must be an object which maps keys to messages.
+// Nested objects are flattened so that their keys are
+// joined by dots.
+func (c *Catalog) Load(locale discordgo.Locale, r io.Reader, unmarshal ...UnmarshalFunc) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	um := UnmarshalFunc(json.Unmarshal)
+	if len(unmarshal) != 0 && unmarshal[0] != nil {
+		um = unmarshal[0]
+	}
+
+	var raw map[string]interface{}
+	if err = um(data, &raw); err != nil {
+		return err
+	}
+
+	messages := make(map[string]string)
+	if err = flatten("", raw, messages); err != nil {
+		return err
+	}
+
+	c.SetAll(locale, messages)
+	return nil
+}
+
+// LoadFile loads the messages from the file at the given
+// location. The locale is taken from the file name without
+// extension, for example "de.json" or "en-US.yaml".
+//
+// See Load for details about the file format.
+func (c *Catalog) LoadFile(loc string, unmarshal ...UnmarshalFunc) error {
+	f, err := os.Open(loc)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	locale, err := localeFromFileName(loc)
+	if err != nil {
+		return err
+	}
+
+	return c.Load(locale, f, unmarshal...)
+}
+
+// LoadFS loads all files in the root directory of the
+// given file system which match the passed pattern
+// (for example "*.json"). Each file contains the
+// messages of the locale specified by its file name.
+//
+// See Load for details about the file format.
+func (c *Catalog) LoadFS(fsys fs.FS, pattern string, unmarshal ...UnmarshalFunc) error {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		locale, err := localeFromFileName(name)
+		if err != nil {
+			return err
+		}
+
+		f, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		err = c.Load(locale, f, unmarshal...)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (c *Catalog) set(locale discordgo.Locale, key, message string) {
+	messages, ok := c.messages[locale]
+	if !ok {
+		messages = make(map[string]string)
+		c.messages[locale] = messages
+	}
+	messages[key] = message
+}
+
+func localeFromFileName(name string) (discordgo.Locale, error) {
+	base := path.Base(strings.ReplaceAll(name, "\\", "/"))
+	locale := discordgo.Locale(strings.TrimSuffix(base, path.Ext(base)))
+	if _, ok := discordgo.Locales[locale]; !ok {
+		return discordgo.Unknown, fmt.Errorf("unknown locale: %s", locale)
+	}
+	return locale, nil
+}
+
+func flatten(prefix string, raw map[string]interface{}, messages map[string]string) error {
+	for key, v := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch vt := v.(type) {
+		case string:
+			messages[key] = vt
+		case map[string]interface{}:
+			if err := flatten(key, vt, messages); err != nil {
+				return err
+			}
+		case map[interface{}]interface{}:
+			m := make(map[string]interface{}, len(vt))
+			for k, v := range vt {
+				m[fmt.Sprint(k)] = v
+			}
+			if err := flatten(key, m, messages); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("invalid message value type for key %s: %T", key, v)
+		}
+	}
+	return nil
+}
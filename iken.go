@@ -1,12 +1,21 @@
 package ken
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"context"
+
+	"github.com/bwmarrin/discordgo"
+)
 
 type IKen interface {
 	Components() *ComponentHandler
 	GetCommandInfo(keyTransformer ...KeyTransformerFunc) (cis CommandInfoList)
+	PlanSync() (plan *SyncPlan, err error)
 	RegisterCommands(cmds ...Command) (err error)
 	RegisterMiddlewares(mws ...interface{}) (err error)
 	Session() *discordgo.Session
+	Sessions() []*discordgo.Session
+	Shutdown(ctx context.Context) (err error)
+	Sync() (err error)
 	Unregister() (err error)
+	UnregisterCommand(name string, types ...discordgo.ApplicationCommandType) (err error)
 }
@@ -46,8 +46,8 @@ type KeyTransformerFunc func(string) string
 // because it uses reflection to inspect external
 // implementations. Because this can be performance
 // straining when the method is called frequently,
-// the result is cached until the number of commands
-// changes.
+// the result is cached until commands are registered
+// or unregistered.
 //
 // If you want to disable this behavior, you can set
 // Config.DisableCommandInfoCache to true on intializing
@@ -60,7 +60,10 @@ func (k *Ken) GetCommandInfo(keyTransformer ...KeyTransformerFunc) (cis CommandI
 		kt = keyTransformer[0]
 	}
 
-	if len(k.cmdInfoCache) == len(k.cmds) {
+	k.cmdsLock.Lock()
+	defer k.cmdsLock.Unlock()
+
+	if k.cmdInfoCache != nil {
 		return k.cmdInfoCache
 	}
 
@@ -74,13 +77,14 @@ func (k *Ken) GetCommandInfo(keyTransformer ...KeyTransformerFunc) (cis CommandI
 }
 
 func (k *Ken) collectCommandInfo(kt KeyTransformerFunc) (cis CommandInfoList) {
-	cis = make(CommandInfoList, 0, len(k.cmds))
-	for _, cmd := range k.cmds {
+	cmds := k.commands()
+	cis = make(CommandInfoList, 0, len(cmds))
+	for _, cmd := range cmds {
 		typ := reflect.TypeOf(cmd)
 		impl := make(map[string][]interface{})
 		for i := 0; i < typ.NumMethod(); i++ {
 			meth := typ.Method(i)
-			if meth.IsExported() && meth.Type.NumIn() == 1 {
+			if meth.IsExported() && meth.Type.NumIn() == 1 && isMethodImplemented(cmd, meth.Name) {
 				vals := meth.Func.Call([]reflect.Value{reflect.ValueOf(cmd)})
 				iVals := make([]interface{}, len(vals))
 				for i, v := range vals {
@@ -90,9 +94,39 @@ func (k *Ken) collectCommandInfo(kt KeyTransformerFunc) (cis CommandInfoList) {
 			}
 		}
 		cis = append(cis, &CommandInfo{
-			ApplicationCommand: toApplicationCommand(cmd),
+			ApplicationCommand: k.toApplicationCommand(cmd),
 			Implementations:    impl,
 		})
 	}
 	return
 }
+
+// optionalCommandInterfaces contains the optional
+// command interfaces which can be reported as not
+// implemented by an optionalCommand.
+var optionalCommandInterfaces = []reflect.Type{
+	typeOf[DmCapable](),
+	typeOf[GuildScopedCommand](),
+	typeOf[MultiGuildScopedCommand](),
+	typeOf[PermissionedCommand](),
+	typeOf[NSFWCommand](),
+	typeOf[ContextAwareCommand](),
+	typeOf[ResponsePolicyCommand](),
+	typeOf[AutocompleteCommand](),
+}
+
+// isMethodImplemented returns false if the method with
+// the given name belongs to an optional interface which
+// cmd reports as not implemented.
+func isMethodImplemented(cmd Command, name string) bool {
+	oc, ok := cmd.(optionalCommand)
+	if !ok {
+		return true
+	}
+	for _, iface := range optionalCommandInterfaces {
+		if _, ok := iface.MethodByName(name); ok && !oc.implements(iface) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,261 @@
+package ken
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// interactionsHandlerDeferAfter is the duration after which
+// an interaction received via HTTP is responded to with a
+// deferred response when no response has been sent by the
+// handler. Discord requires an initial response within
+// three seconds.
+const interactionsHandlerDeferAfter = 2500 * time.Millisecond
+
+// interactionsHandlerMaxBodySize is the maximum size of
+// request bodies accepted by the InteractionsHandler.
+const interactionsHandlerMaxBodySize = 4 << 20
+
+// errInteractionDeferred is returned when an interaction
+// has already been acknowledged with a deferred response
+// because the handler did not respond in time.
+var errInteractionDeferred = errors.New("interaction has already been deferred")
+
+// InteractionsHandler implements http.Handler to receive
+// interactions via Discord's interactions endpoint instead
+// of the gateway.
+//
+// Incoming requests are verified using the public key of
+// the application. Interactions are passed to the same
+// command, autocomplete and component pipeline as
+// interactions received via the gateway. The initial
+// response to an interaction is written as the HTTP
+// response body. Subsequent responses and follow up
+// messages are sent via the REST API using the Session
+// of Ken, which therefore does not need to be connected
+// to the gateway.
+//
+// Without a gateway connection, no Ready event is received
+// to synchronize the registered commands. Therefore,
+// Ken.Sync must be called after registering the commands.
+type InteractionsHandler struct {
+	ken       *Ken
+	publicKey ed25519.PublicKey
+}
+
+var _ http.Handler = (*InteractionsHandler)(nil)
+
+// NewInteractionsHandler returns a new instance of
+// InteractionsHandler using the given instance of Ken
+// and the public key of the application.
+func NewInteractionsHandler(ken *Ken, publicKey ed25519.PublicKey) *InteractionsHandler {
+	return &InteractionsHandler{
+		ken:       ken,
+		publicKey: publicKey,
+	}
+}
+
+func (h *InteractionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, interactionsHandlerMaxBodySize)
+
+	if !discordgo.VerifyInteraction(r, h.publicKey) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	if h.ken.isClosed() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	var e discordgo.InteractionCreate
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if e.Type == discordgo.InteractionPing {
+		h.writeResponse(w, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponsePong,
+		})
+		return
+	}
+
+	res := newHTTPResponder()
+	h.ken.httpResponders.Store(e.ID, res)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer h.ken.httpResponders.Delete(e.ID)
+		h.ken.onInteractionCreate(h.ken.s, &e)
+		h.ken.componentHandler.handle(h.ken.s, &e)
+	}()
+
+	timer := time.NewTimer(interactionsHandlerDeferAfter)
+	defer timer.Stop()
+
+	select {
+	case resp := <-res.c:
+		h.writeResponse(w, resp)
+	case <-done:
+		select {
+		case resp := <-res.c:
+			h.writeResponse(w, resp)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case <-timer.C:
+		deferType, ok := deferredResponseType(e.Type)
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		// Deferring through the context of the interaction
+		// keeps its ephemeral state. Without a context, the
+		// interaction is deferred directly.
+		if !res.deferContext() && res.deferResponse() {
+			h.writeResponse(w, &discordgo.InteractionResponse{
+				Type: deferType,
+			})
+			return
+		}
+		h.writeResponse(w, <-res.c)
+	}
+}
+
+func (h *InteractionsHandler) writeResponse(w http.ResponseWriter, resp *discordgo.InteractionResponse) {
+	if resp.Data != nil && len(resp.Data.Files) > 0 {
+		contentType, body, err := discordgo.MultipartBodyWithJSON(resp, resp.Data.Files)
+		if err != nil {
+			h.ken.opt.OnSystemError("interaction response", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(body)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.ken.opt.OnSystemError("interaction response", err)
+	}
+}
+
+// httpResponder passes the initial response to an
+// interaction received via HTTP to the InteractionsHandler.
+type httpResponder struct {
+	mtx          sync.Mutex
+	c            chan *discordgo.InteractionResponse
+	acknowledged bool
+
+	ctxMtx sync.Mutex
+	ctx    *ctxResponder
+}
+
+func newHTTPResponder() *httpResponder {
+	return &httpResponder{
+		c: make(chan *discordgo.InteractionResponse, 1),
+	}
+}
+
+// respond passes the response to the InteractionsHandler.
+// If the interaction has already been deferred,
+// errInteractionDeferred is returned.
+func (r *httpResponder) respond(resp *discordgo.InteractionResponse) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.acknowledged {
+		return errInteractionDeferred
+	}
+	r.acknowledged = true
+	r.c <- resp
+	return nil
+}
+
+// deferResponse marks the interaction as acknowledged and
+// returns true if no response has been passed before.
+func (r *httpResponder) deferResponse() bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.acknowledged {
+		return false
+	}
+	r.acknowledged = true
+	return true
+}
+
+// attach sets the context handling the interaction.
+func (r *httpResponder) attach(ctx *ctxResponder) {
+	r.ctxMtx.Lock()
+	defer r.ctxMtx.Unlock()
+	r.ctx = ctx
+}
+
+// detach removes the context handling the interaction
+// and waits for a running deferContext call to finish.
+func (r *httpResponder) detach() {
+	r.ctxMtx.Lock()
+	defer r.ctxMtx.Unlock()
+	r.ctx = nil
+}
+
+// deferContext defers the interaction using the attached
+// context, if any, and returns true. Otherwise, false is
+// returned.
+func (r *httpResponder) deferContext() bool {
+	r.ctxMtx.Lock()
+	defer r.ctxMtx.Unlock()
+
+	if r.ctx == nil {
+		return false
+	}
+	r.ctx.autoDefer()
+	return true
+}
+
+// interactionRespond sends the initial response to the
+// given interaction. When the interaction has been received
+// via the InteractionsHandler, the response is written as
+// HTTP response. Otherwise, the response is sent via the
+// REST API.
+func (k *Ken) interactionRespond(
+	s *discordgo.Session,
+	i *discordgo.Interaction,
+	resp *discordgo.InteractionResponse,
+) error {
+	if v, ok := k.httpResponders.Load(i.ID); ok {
+		return v.(*httpResponder).respond(resp)
+	}
+	return s.InteractionRespond(i, resp)
+}
+
+func isDeferredResponseType(typ discordgo.InteractionResponseType) bool {
+	return typ == discordgo.InteractionResponseDeferredChannelMessageWithSource ||
+		typ == discordgo.InteractionResponseDeferredMessageUpdate
+}
+
+func deferredResponseType(typ discordgo.InteractionType) (discordgo.InteractionResponseType, bool) {
+	switch typ {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionModalSubmit:
+		return discordgo.InteractionResponseDeferredChannelMessageWithSource, true
+	case discordgo.InteractionMessageComponent:
+		return discordgo.InteractionResponseDeferredMessageUpdate, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,148 @@
+package ken
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type testInteractionsServer struct {
+	t       *testing.T
+	handler *InteractionsHandler
+	key     ed25519.PrivateKey
+}
+
+func newTestInteractionsServer(t *testing.T, cmds ...Command) *testInteractionsServer {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := New(&discordgo.Session{State: discordgo.NewState()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = k.RegisterCommands(cmds...); err != nil {
+		t.Fatal(err)
+	}
+
+	return &testInteractionsServer{
+		t:       t,
+		handler: NewInteractionsHandler(k, pub),
+		key:     priv,
+	}
+}
+
+func (s *testInteractionsServer) do(body []byte, sign bool) *httptest.ResponseRecorder {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	key := s.key
+	if !sign {
+		_, key, _ = ed25519.GenerateKey(rand.Reader)
+	}
+	sig := ed25519.Sign(key, append([]byte(timestamp), body...))
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	r.Header.Set("X-Signature-Ed25519", hex.EncodeToString(sig))
+	r.Header.Set("X-Signature-Timestamp", timestamp)
+
+	w := httptest.NewRecorder()
+	s.handler.ServeHTTP(w, r)
+	return w
+}
+
+func (s *testInteractionsServer) decode(w *httptest.ResponseRecorder) *discordgo.InteractionResponse {
+	if w.Code != http.StatusOK {
+		s.t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp discordgo.InteractionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		s.t.Fatal(err)
+	}
+	return &resp
+}
+
+func testCommandInteraction(name string) []byte {
+	id := (time.Now().UnixMilli() - 1420070400000) << 22
+	body, _ := json.Marshal(map[string]interface{}{
+		"id":             strconv.FormatInt(id, 10),
+		"application_id": "1",
+		"type":           discordgo.InteractionApplicationCommand,
+		"token":          "token",
+		"guild_id":       "2",
+		"channel_id":     "3",
+		"data": map[string]interface{}{
+			"id":   "4",
+			"name": name,
+			"type": discordgo.ChatApplicationCommand,
+		},
+	})
+	return body
+}
+
+func TestInteractionsHandlerSignature(t *testing.T) {
+	s := newTestInteractionsServer(t)
+	body := []byte(`{"type":1}`)
+
+	if w := s.do(body, false); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 for invalid signature, got %d", w.Code)
+	}
+
+	if resp := s.decode(s.do(body, true)); resp.Type != discordgo.InteractionResponsePong {
+		t.Fatalf("expected pong response, got %d", resp.Type)
+	}
+}
+
+func TestInteractionsHandlerCommandResponse(t *testing.T) {
+	s := newTestInteractionsServer(t,
+		NewSlash("ping").Description("Ping pong").Handler(func(ctx Context) error {
+			return ctx.RespondMessage("pong")
+		}))
+
+	resp := s.decode(s.do(testCommandInteraction("ping"), true))
+	if resp.Type != discordgo.InteractionResponseChannelMessageWithSource {
+		t.Fatalf("expected message response, got %d", resp.Type)
+	}
+	if resp.Data == nil || resp.Data.Content != "pong" {
+		t.Fatalf("expected content pong, got %+v", resp.Data)
+	}
+}
+
+func TestInteractionsHandlerDeferKeepsEphemeral(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	s := newTestInteractionsServer(t,
+		NewSlash("slow").Description("Slow").
+			ResponsePolicy(ResponsePolicy{Ephemeral: true}).
+			Handler(func(ctx Context) error {
+				<-release
+				return nil
+			}))
+
+	resp := s.decode(s.do(testCommandInteraction("slow"), true))
+	if resp.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Fatalf("expected deferred response, got %d", resp.Type)
+	}
+	if resp.Data == nil || resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Fatalf("expected ephemeral deferred response, got %+v", resp.Data)
+	}
+}
+
+func TestInteractionsHandlerBodyLimit(t *testing.T) {
+	s := newTestInteractionsServer(t)
+	body := bytes.Repeat([]byte(" "), interactionsHandlerMaxBodySize+1)
+
+	if w := s.do(body, true); w.Code == http.StatusOK {
+		t.Fatal("expected oversized request to be rejected")
+	}
+}
@@ -4,10 +4,13 @@
 package ken
 
 import (
+	"context"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken/i18n"
 	"github.com/zekrotja/ken/state"
 	"github.com/zekrotja/ken/store"
 	"github.com/zekrotja/safepool"
@@ -37,6 +40,20 @@ type Options struct {
 	DependencyProvider ObjectProvider
 	// EmbedColors lets you define custom colors for embeds.
 	EmbedColors EmbedColors
+	// CommandCatalog specifies a translation catalog
+	// which is used to localize the names, descriptions,
+	// options and choices of registered commands.
+	CommandCatalog *i18n.Catalog
+	// MessageCatalog specifies a translation catalog
+	// which is used to look up the user-facing messages
+	// sent by Ken and its middlewares as well as the
+	// messages returned by Ctx.T.
+	MessageCatalog *i18n.Catalog
+	// DefaultLocale specifies the locale which is used
+	// to look up messages in the MessageCatalog when no
+	// message exists for the locale of the user or the
+	// guild of an interaction.
+	DefaultLocale discordgo.Locale
 	// DisableCommandInfoCache disabled caching
 	// the result of Ken#GetCommandInfo() after
 	// first call of the method.
@@ -50,22 +67,54 @@ type Options struct {
 	// OnCommandError is called when an error occurs
 	// during middleware or command execution.
 	OnCommandError func(err error, ctx *Ctx)
+	// ErrorResponder is called with errors returned by
+	// commands and by middlewares which canceled the
+	// command execution to respond them to the user.
+	// The returned error is passed to OnCommandError.
+	//
+	// Defaults to DefaultErrorResponder.
+	ErrorResponder func(err error, ctx *Ctx) error
 	// OnEventError is called when any other user
 	// event based error occured.
 	OnEventError func(context string, err error)
+	// OnPanic is called when a panic has been
+	// recovered in a command, middleware, autocomplete
+	// or component handler.
+	OnPanic func(p *Panic)
 }
 
 // Ken is the handler to register, manage and
 // life-cycle commands as well as middlewares.
 type Ken struct {
-	s   *discordgo.Session
-	opt *Options
+	s        *discordgo.Session
+	sessions []*discordgo.Session
+	opt      *Options
+
+	rootCtx         context.Context
+	cancelRoot      context.CancelFunc
+	handlerRemovers []func()
+	lifecycleMtx    sync.RWMutex
+	closed          bool
+	inflight        sync.WaitGroup
+
+	cmdsLock     sync.RWMutex
+	cmds         map[commandKey]Command
+	cmdInfoCache CommandInfoList
+
+	// syncMtx serializes the requests which create, update
+	// or delete application commands and guards the idcache.
+	// cmdsLock is only acquired after syncMtx and is never
+	// held during requests, so that the dispatch of
+	// interactions is not blocked by them.
+	syncMtx        sync.Mutex
+	appID          string
+	idcache        map[commandKey]*store.CommandEntry
+	foreignEntries []*store.CommandEntry
+	loadedEntries  []*store.CommandEntry
+	hashesStored   bool
 
-	cmdsLock         sync.RWMutex
-	cmds             map[string]Command
-	idcache          map[string]string
-	cmdInfoCache     CommandInfoList
 	componentHandler *ComponentHandler
+	httpResponders   sync.Map
 
 	ctxPool             safepool.SafePool[*Ctx]
 	subCtxPool          safepool.SafePool[*subCommandCtx]
@@ -75,35 +124,81 @@ type Ken struct {
 	mwAfter  []MiddlewareAfter
 }
 
-var defaultOptions = Options{
-	State: state.NewInternal(),
-	EmbedColors: EmbedColors{
-		Default: 0xFDD835,
-		Error:   0xF44336,
-	},
-	DisableCommandInfoCache: false,
-	OnSystemError: func(ctx string, err error, args ...interface{}) {
-		log.Printf("[KEN] {%s} - %s\n", ctx, err.Error())
-	},
-	OnCommandError: func(err error, ctx *Ctx) {
-		log.Printf("[KEN] {command error} - %s : %s\n", ctx.Command.Name(), err.Error())
-	},
-	OnEventError: func(context string, err error) {
-		log.Printf("[KEN] {event error} - %s : %s\n", context, err.Error())
-	},
+// newDefaultOptions returns a new instance of the
+// default options.
+func newDefaultOptions() *Options {
+	return &Options{
+		State: state.NewInternal(),
+		EmbedColors: EmbedColors{
+			Default: 0xFDD835,
+			Error:   0xF44336,
+		},
+		DefaultLocale:           discordgo.EnglishUS,
+		DisableCommandInfoCache: false,
+		OnSystemError: func(ctx string, err error, args ...interface{}) {
+			log.Printf("[KEN] {%s} - %s\n", ctx, err.Error())
+		},
+		OnCommandError: func(err error, ctx *Ctx) {
+			log.Printf("[KEN] {command error} - %s : %s\n", ctx.Command.Name(), err.Error())
+		},
+		ErrorResponder: DefaultErrorResponder,
+		OnEventError: func(context string, err error) {
+			log.Printf("[KEN] {event error} - %s : %s\n", context, err.Error())
+		},
+		OnPanic: func(p *Panic) {
+			log.Printf("[KEN] {panic} - %s : %v\n%s", p.Context, p.Value, p.Stack)
+		},
+	}
 }
 
 // New initializes a new instance of Ken with
 // the passed discordgo Session s and optional
-// Options.
+// options.
+//
+// Options can either be passed as Options struct,
+// where only non-zero fields are applied, or as
+// functional options like WithState or WithStore.
+// Options are applied in the passed order.
+//
+// If no options are passed, default parameters
+// will be applied.
+func New(s *discordgo.Session, options ...Option) (k *Ken, err error) {
+	return NewSharded([]*discordgo.Session{s}, options...)
+}
+
+// NewSharded initializes a new instance of Ken with
+// the passed discordgo Sessions, for example one for
+// each shard of the bot, and optional Options.
+//
+// Interactions are handled on every passed session
+// and are responded to using the session which
+// received them. Commands are only synchronized
+// once on the first received Ready event.
 //
 // If no options are passed, default parameters
 // will be applied.
-func New(s *discordgo.Session, options ...Options) (k *Ken, err error) {
+func NewSharded(sessions []*discordgo.Session, options ...Option) (k *Ken, err error) {
+	if len(sessions) == 0 {
+		err = ErrNoSessions
+		return
+	}
+
+	opt := newDefaultOptions()
+	for _, o := range options {
+		if err = o.apply(opt); err != nil {
+			return
+		}
+	}
+	if err = opt.validate(); err != nil {
+		return
+	}
+
 	k = &Ken{
-		s:                   s,
-		cmds:                make(map[string]Command),
-		idcache:             make(map[string]string),
+		opt:                 opt,
+		s:                   sessions[0],
+		sessions:            sessions,
+		cmds:                make(map[commandKey]Command),
+		idcache:             make(map[commandKey]*store.CommandEntry),
 		mwBefore:            make([]MiddlewareBefore, 0),
 		mwAfter:             make([]MiddlewareAfter, 0),
 		ctxPool:             safepool.New(newCtx),
@@ -111,46 +206,23 @@ func New(s *discordgo.Session, options ...Options) (k *Ken, err error) {
 		autoCompleteCtxPool: safepool.New(newAutocompleteContext),
 	}
 
-	k.componentHandler = NewComponentHandler(k)
-
-	k.opt = &defaultOptions
-	if len(options) > 0 {
-		o := options[0]
-
-		k.opt.DependencyProvider = o.DependencyProvider
-
-		if o.State != nil {
-			k.opt.State = o.State
-		}
-		if o.CommandStore != nil {
-			k.opt.CommandStore = o.CommandStore
-		}
-		if o.DisableCommandInfoCache {
-			k.opt.DisableCommandInfoCache = true
-		}
-		if o.OnSystemError != nil {
-			k.opt.OnSystemError = o.OnSystemError
-		}
-		if o.OnCommandError != nil {
-			k.opt.OnCommandError = o.OnCommandError
-		}
-		if o.EmbedColors.Default > 0 {
-			k.opt.EmbedColors.Default = o.EmbedColors.Default
-		}
-		if o.EmbedColors.Error > 0 {
-			k.opt.EmbedColors.Error = o.EmbedColors.Error
-		}
-	}
-
 	if k.opt.CommandStore != nil {
-		k.idcache, err = k.opt.CommandStore.Load()
-		if err != nil {
+		if err = k.loadIdcache(); err != nil {
 			return
 		}
 	}
 
-	k.s.AddHandler(k.onReady)
-	k.s.AddHandler(k.onInteractionCreate)
+	// Handlers are only added after all fallible steps,
+	// so that no handlers are left attached to the
+	// sessions when an error is returned.
+	k.rootCtx, k.cancelRoot = context.WithCancel(context.Background())
+	k.componentHandler = NewComponentHandler(k)
+
+	for _, s := range k.sessions {
+		k.handlerRemovers = append(k.handlerRemovers,
+			s.AddHandler(k.onReady),
+			s.AddHandler(k.onInteractionCreate))
+	}
 
 	return
 }
@@ -158,11 +230,18 @@ func New(s *discordgo.Session, options ...Options) (k *Ken, err error) {
 // RegisterCommands registers the passed commands to
 // the command register.
 //
-// Keep in mind that commands are registered by Name,
-// so there can be only one single command per name.
+// Commands are registered by their type, scope and
+// name, so a slash command, a user command and a
+// message command can share the same name.
+//
+// When the commands are registered after the Ready
+// event has been received, they are immediately
+// created as application commands.
 func (k *Ken) RegisterCommands(cmds ...Command) (err error) {
-	k.cmdsLock.Lock()
-	defer k.cmdsLock.Unlock()
+	k.syncMtx.Lock()
+	defer k.syncMtx.Unlock()
+
+	defer k.storeIdcache()
 
 	for _, c := range cmds {
 		err = k.registerCommand(c)
@@ -175,6 +254,55 @@ func (k *Ken) RegisterCommands(cmds ...Command) (err error) {
 	return
 }
 
+// UnregisterCommand removes the commands with the given
+// name from the command register. If types are passed,
+// only commands of the given types are removed.
+//
+// When the command is unregistered after the Ready
+// event has been received, the corresponding application
+// commands are immediately deleted.
+// Commands of which the application command could not
+// be deleted stay registered.
+func (k *Ken) UnregisterCommand(name string, types ...discordgo.ApplicationCommandType) (err error) {
+	k.syncMtx.Lock()
+	defer k.syncMtx.Unlock()
+
+	var keys []commandKey
+	k.cmdsLock.RLock()
+	for ck := range k.cmds {
+		if ck.Name == name && (len(types) == 0 || containsType(types, ck.Type)) {
+			keys = append(keys, ck)
+		}
+	}
+	k.cmdsLock.RUnlock()
+
+	if len(keys) == 0 {
+		err = ErrCommandNotFound
+		return
+	}
+
+	defer k.storeIdcache()
+
+	// Commands are only removed from the register after
+	// the corresponding application command has been
+	// deleted, so that a failed deletion does not leave
+	// an unhandled command on Discord.
+	for _, ck := range keys {
+		if e, ok := k.idcache[ck]; ok && k.appID != "" {
+			if err = k.s.ApplicationCommandDelete(k.appID, ck.GuildID, e.ID); err != nil {
+				return
+			}
+			delete(k.idcache, ck)
+		}
+		k.cmdsLock.Lock()
+		delete(k.cmds, ck)
+		k.cmdInfoCache = nil
+		k.cmdsLock.Unlock()
+	}
+
+	return
+}
+
 // RegisterMiddlewares allows to register passed
 // commands to the middleware callstack.
 //
@@ -212,24 +340,82 @@ func (k *Ken) Unregister() (err error) {
 	if err != nil {
 		return
 	}
-	for _, id := range k.idcache {
-		if err = k.s.ApplicationCommandDelete(self.ID, "", id); err != nil {
+
+	k.syncMtx.Lock()
+	defer k.syncMtx.Unlock()
+
+	for _, e := range k.idcache {
+		if err = k.s.ApplicationCommandDelete(self.ID, e.GuildID, e.ID); err != nil {
 			k.opt.OnSystemError("command unregister", err)
 		}
 	}
 	return
 }
 
+// Shutdown stops handling new interactions and removes
+// all event handlers of Ken, including the ones of the
+// ComponentHandler, from the Discordgo Sessions.
+//
+// After that, Shutdown waits until all running commands,
+// middlewares, autocomplete and component handlers have
+// finished and flushes the CommandStore, if specified.
+//
+// When the passed context is done before all handlers
+// have finished, the contexts passed to the handlers are
+// canceled, the CommandStore is flushed anyway and the
+// error of the context is returned.
+func (k *Ken) Shutdown(ctx context.Context) (err error) {
+	k.lifecycleMtx.Lock()
+	wasClosed := k.closed
+	k.closed = true
+	k.lifecycleMtx.Unlock()
+
+	if !wasClosed {
+		for _, remove := range k.handlerRemovers {
+			remove()
+		}
+		k.componentHandler.UnregisterDiscordHandler()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		k.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	k.cancelRoot()
+
+	k.syncMtx.Lock()
+	defer k.syncMtx.Unlock()
+	k.storeIdcache()
+
+	return
+}
+
 // Components returns the component handler.
 func (k *Ken) Components() *ComponentHandler {
 	return k.componentHandler
 }
 
 // Session returns the internal Discordgo session.
+//
+// When multiple sessions have been passed, the
+// first session is returned.
 func (k *Ken) Session() *discordgo.Session {
 	return k.s
 }
 
+// Sessions returns all internal Discordgo sessions.
+func (k *Ken) Sessions() []*discordgo.Session {
+	return k.sessions
+}
+
 // --- Internal API ---
 
 func (k *Ken) registerCommand(cmd Command) (err error) {
@@ -237,16 +423,148 @@ func (k *Ken) registerCommand(cmd Command) (err error) {
 		err = ErrEmptyCommandName
 		return
 	}
-	if _, ok := k.cmds[cmd.Name()]; ok {
-		err = ErrCommandAlreadyRegistered
+
+	typ := commandType(cmd)
+	guilds := commandGuilds(cmd)
+	k.cmdsLock.RLock()
+	for _, guildID := range guilds {
+		if _, ok := k.cmds[newCommandKey(typ, guildID, cmd.Name())]; ok {
+			err = ErrCommandAlreadyRegistered
+			break
+		}
+	}
+	k.cmdsLock.RUnlock()
+	if err != nil {
 		return
 	}
 
-	k.cmds[cmd.Name()] = cmd
+	if k.appID != "" {
+		var created []commandKey
+		for _, guildID := range guilds {
+			acmd := k.scopedApplicationCommand(cmd, guildID)
+			var ccmd *discordgo.ApplicationCommand
+			ccmd, err = k.s.ApplicationCommandCreate(k.appID, guildID, acmd)
+			if err != nil {
+				k.deleteCreatedCommands(created)
+				return
+			}
+			k.cacheCommand(k.appID, guildID, acmd, ccmd.ID, applicationCommandHash(acmd))
+			created = append(created, applicationCommandKey(guildID, acmd))
+		}
+	}
+
+	k.cmdsLock.Lock()
+	for _, guildID := range guilds {
+		k.cmds[newCommandKey(typ, guildID, cmd.Name())] = cmd
+	}
+	k.cmdInfoCache = nil
+	k.cmdsLock.Unlock()
 
 	return
 }
 
+// deleteCreatedCommands rolls back the creation of the
+// application commands with the passed keys after the
+// registration of a command has failed.
+//
+// Commands which could not be deleted are kept in the
+// idcache, so that they can be removed on the next
+// sync.
+func (k *Ken) deleteCreatedCommands(keys []commandKey) {
+	for _, ck := range keys {
+		e, ok := k.idcache[ck]
+		if !ok {
+			continue
+		}
+		if err := k.s.ApplicationCommandDelete(k.appID, ck.GuildID, e.ID); err != nil {
+			k.opt.OnSystemError("command rollback", err)
+			continue
+		}
+		delete(k.idcache, ck)
+	}
+}
+
+// acquireInteraction registers an interaction as being
+// handled and returns true. If Ken has been shut down,
+// false is returned and the interaction must be ignored.
+//
+// Each successful call must be followed by a call to
+// releaseInteraction when the interaction is handled.
+func (k *Ken) acquireInteraction() bool {
+	k.lifecycleMtx.RLock()
+	defer k.lifecycleMtx.RUnlock()
+
+	if k.closed {
+		return false
+	}
+	k.inflight.Add(1)
+	return true
+}
+
+func (k *Ken) releaseInteraction() {
+	k.inflight.Done()
+}
+
+// interactionContext returns a context derived from the
+// root context which is done when the given window after
+// the creation of the interaction has passed.
+func (k *Ken) interactionContext(
+	i *discordgo.Interaction,
+	window time.Duration,
+) (context.Context, context.CancelFunc) {
+	created, err := discordgo.SnowflakeTimestamp(i.ID)
+	if err != nil {
+		created = time.Now()
+	}
+	return context.WithDeadline(k.rootCtx, created.Add(window))
+}
+
+func (k *Ken) isClosed() bool {
+	k.lifecycleMtx.RLock()
+	defer k.lifecycleMtx.RUnlock()
+	return k.closed
+}
+
+// getCommand returns the registered command by type and
+// name which is registered on the given guild. If no
+// such command exists, the global command is returned.
+func (k *Ken) getCommand(
+	typ discordgo.ApplicationCommandType,
+	guildID, name string,
+) Command {
+	k.cmdsLock.RLock()
+	defer k.cmdsLock.RUnlock()
+
+	if guildID != "" {
+		if cmd, ok := k.cmds[newCommandKey(typ, guildID, name)]; ok {
+			return cmd
+		}
+	}
+	return k.cmds[newCommandKey(typ, "", name)]
+}
+
+// commands returns all registered commands. Commands
+// which are registered in multiple scopes are only
+// contained once.
+func (k *Ken) commands() []Command {
+	cmds := make([]Command, 0, len(k.cmds))
+	for ck, cmd := range k.cmds {
+		if ck.GuildID == commandGuilds(cmd)[0] {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
+
+func containsType(types []discordgo.ApplicationCommandType, typ discordgo.ApplicationCommandType) bool {
+	for _, t := range types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
 func (k *Ken) registerMiddleware(mw interface{}) (err error) {
 	var (
 		okBefore, okAfter bool
@@ -266,60 +584,26 @@ func (k *Ken) registerMiddleware(mw interface{}) (err error) {
 }
 
 func (k *Ken) onReady(s *discordgo.Session, e *discordgo.Ready) {
-	k.cmdsLock.RLock()
-	defer k.cmdsLock.RUnlock()
-
-	var (
-		ccmd   *discordgo.ApplicationCommand
-		err    error
-		update = []*discordgo.ApplicationCommand{}
-	)
-
-	for name, cmd := range k.cmds {
-		guildId := ""
-		if gsc, ok := cmd.(GuildScopedCommand); ok {
-			guildId = gsc.Guild()
-		}
-
-		if _, ok := k.idcache[name]; ok {
-			acmd := toApplicationCommand(cmd)
-			update = append(update, acmd)
-		} else {
-			ccmd, err = s.ApplicationCommandCreate(e.User.ID, guildId, toApplicationCommand(cmd))
-			if err != nil {
-				k.opt.OnSystemError("command registration", err)
-			} else {
-				k.idcache[name] = ccmd.ID
-			}
-		}
-	}
-
-	if len(update) > 0 {
-		_, err = s.ApplicationCommandBulkOverwrite(e.User.ID, "", update)
-		if err != nil {
-			k.opt.OnSystemError("command update", err)
-		}
-	}
-
-	for name, id := range k.idcache {
-		if _, ok := k.cmds[name]; !ok {
-			delete(k.idcache, name)
-			err = s.ApplicationCommandDelete(e.User.ID, "", id)
-			if err != nil {
-				k.opt.OnSystemError("command delete", err)
-			}
-		}
+	k.syncMtx.Lock()
+	defer k.syncMtx.Unlock()
+
+	// Commands are only synchronized on the first
+	// Ready event because subsequent ones are
+	// either reconnects or the Ready events of
+	// other shards of the same application.
+	if k.appID != "" {
+		return
 	}
 
-	if k.opt.CommandStore != nil {
-		err = k.opt.CommandStore.Store(k.idcache)
-		if err != nil {
-			k.opt.OnSystemError("idcache storage", err)
-		}
-	}
+	k.syncCommands(s, e.User.ID)
 }
 
 func (k *Ken) onInteractionCreate(s *discordgo.Session, e *discordgo.InteractionCreate) {
+	if !k.acquireInteraction() {
+		return
+	}
+	defer k.releaseInteraction()
+
 	switch e.Type {
 	case discordgo.InteractionApplicationCommand:
 		k.onInteractionApplicationCommand(s, e)
@@ -332,36 +616,48 @@ func (k *Ken) onInteractionApplicationCommand(
 	s *discordgo.Session,
 	e *discordgo.InteractionCreate,
 ) {
-	k.cmdsLock.RLock()
-	cmd := k.cmds[e.ApplicationCommandData().Name]
-	k.cmdsLock.RUnlock()
-
+	data := e.ApplicationCommandData()
+	cmd := k.getCommand(data.CommandType, e.GuildID, data.Name)
 	if cmd == nil {
 		return
 	}
 
-	ch, err := k.opt.State.Channel(s, e.ChannelID)
-	if err != nil {
-		k.opt.OnSystemError("state error", err)
-		return
-	}
-
 	ctx := k.ctxPool.Get()
 	defer k.ctxPool.Put(ctx)
 
 	ctx.responded = false
+	ctx.deferred = false
 	ctx.ken = k
 	ctx.session = s
 	ctx.event = e
 	ctx.Command = cmd
 	ctx.ephemeral = false
 
-	if rpCmd, ok := cmd.(ResponsePolicyCommand); ok {
-		ctx.ephemeral = rpCmd.ResponsePolicy().Ephemeral
+	var cancel context.CancelFunc
+	ctx.ctx, cancel = k.interactionContext(e.Interaction, interactionTokenLifetime)
+	defer cancel()
+
+	defer ctx.attachHTTPResponder()()
+	defer k.recoverPanic("command", e.Interaction, &ctx.ctxResponder)
+
+	if rpCmd, ok := asOptional[ResponsePolicyCommand](cmd); ok {
+		policy := rpCmd.ResponsePolicy()
+		ctx.SetEphemeral(policy.Ephemeral)
+		if policy.AutoDeferAfter > 0 {
+			stop := ctx.startAutoDefer(policy.AutoDeferAfter)
+			defer stop()
+		}
 	}
 
-	if ch.Type == discordgo.ChannelTypeDM || ch.Type == discordgo.ChannelTypeGroupDM {
-		if dmCmd, ok := cmd.(DmCapable); !ok || !dmCmd.IsDmCapable() {
+	// Interactions which have been created outside
+	// of a guild are always either in a DM or in
+	// a group DM.
+	if e.GuildID == "" {
+		if !isDmCapable(cmd) {
+			ctx.SetEphemeral(true)
+			if err := ctx.RespondError(ctx.T(MessageNotDMCapable), ctx.T(MessageErrorTitle)); err != nil {
+				k.opt.OnSystemError("response error", err)
+			}
 			k.opt.OnCommandError(ErrNotDMCapable, ctx)
 			return
 		}
@@ -369,6 +665,9 @@ func (k *Ken) onInteractionApplicationCommand(
 
 	for _, mw := range k.mwBefore {
 		next, err := mw.Before(ctx)
+		if err != nil && !next {
+			err = k.opt.ErrorResponder(err, ctx)
+		}
 		if err != nil {
 			k.opt.OnCommandError(err, ctx)
 		}
@@ -377,9 +676,11 @@ func (k *Ken) onInteractionApplicationCommand(
 		}
 	}
 
-	err = cmd.Run(ctx)
+	err := cmd.Run(ctx)
 	if err != nil {
-		k.opt.OnCommandError(err, ctx)
+		if rErr := k.opt.ErrorResponder(err, ctx); rErr != nil {
+			k.opt.OnCommandError(rErr, ctx)
+		}
 	}
 
 	for _, mw := range k.mwAfter {
@@ -391,15 +692,13 @@ func (k *Ken) onInteractionApplicationCommand(
 }
 
 func (k *Ken) onInteractionAutoComplete(s *discordgo.Session, e *discordgo.InteractionCreate) {
-	k.cmdsLock.RLock()
-	cmd := k.cmds[e.ApplicationCommandData().Name]
-	k.cmdsLock.RUnlock()
-
+	data := e.ApplicationCommandData()
+	cmd := k.getCommand(data.CommandType, e.GuildID, data.Name)
 	if cmd == nil {
 		return
 	}
 
-	autocompleteCmd, ok := cmd.(AutocompleteCommand)
+	autocompleteCmd, ok := asOptional[AutocompleteCommand](cmd)
 	if !ok {
 		return
 	}
@@ -410,13 +709,19 @@ func (k *Ken) onInteractionAutoComplete(s *discordgo.Session, e *discordgo.Inter
 	ctx.session = s
 	ctx.event = e
 
+	var cancel context.CancelFunc
+	ctx.ctx, cancel = k.interactionContext(e.Interaction, interactionResponseWindow)
+	defer cancel()
+
+	defer k.recoverPanic("autocomplete", e.Interaction, nil)
+
 	choises, err := autocompleteCmd.Autocomplete(ctx)
 	if err != nil {
 		k.opt.OnEventError("command autocomplete call failed", err)
 		return
 	}
 
-	err = s.InteractionRespond(e.Interaction, &discordgo.InteractionResponse{
+	err = k.interactionRespond(s, e.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
 		Data: &discordgo.InteractionResponseData{
 			Choices: choises,
@@ -0,0 +1,157 @@
+package ken
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// newTestRestKen returns a Ken instance with an application
+// ID set, which passes all REST requests to handle. handle
+// returns the status code and body of the response.
+//
+// System errors are ignored unless other options are passed.
+func newTestRestKen(t *testing.T, handle func(r *http.Request) (int, string), options ...Option) *Ken {
+	t.Helper()
+
+	session, err := discordgo.New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session.Client = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		status, body := handle(r)
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	})}
+
+	options = append([]Option{WithOnSystemError(func(string, error, ...interface{}) {})}, options...)
+	k, err := New(session, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.appID = "1"
+	return k
+}
+
+func TestRegisterCommandRollsBackCreatedCommands(t *testing.T) {
+	var requests []string
+	k := newTestRestKen(t, func(r *http.Request) (int, string) {
+		requests = append(requests, r.Method+" "+r.URL.String())
+		switch {
+		case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/guilds/10/"):
+			return http.StatusOK, `{"id":"100","name":"ping","type":1}`
+		case r.Method == http.MethodPost:
+			return http.StatusBadRequest, `{"code":50035,"message":"Invalid Form Body"}`
+		case r.Method == http.MethodDelete:
+			return http.StatusNoContent, ""
+		}
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		return http.StatusNotFound, "{}"
+	})
+
+	cmd := NewSlash("ping").Description("Ping pong").Guilds("10", "20").Handler(func(ctx Context) error {
+		return nil
+	})
+	if err := k.RegisterCommands(cmd); err == nil {
+		t.Fatal("expected registration to fail")
+	}
+
+	want := []string{
+		"POST " + discordgo.EndpointApplicationGuildCommands("1", "10"),
+		"POST " + discordgo.EndpointApplicationGuildCommands("1", "20"),
+		"DELETE " + discordgo.EndpointApplicationGuildCommand("1", "10", "100"),
+	}
+	if fmt.Sprint(requests) != fmt.Sprint(want) {
+		t.Errorf("expected requests %v, got %v", want, requests)
+	}
+	if len(k.cmds) != 0 {
+		t.Errorf("expected no registered commands, got %d", len(k.cmds))
+	}
+	if len(k.idcache) != 0 {
+		t.Errorf("expected no cached commands, got %d", len(k.idcache))
+	}
+}
+
+func TestUnregisterCommandKeepsCommandOnFailedDelete(t *testing.T) {
+	deleteFails := false
+	k := newTestRestKen(t, func(r *http.Request) (int, string) {
+		switch {
+		case r.Method == http.MethodPost:
+			return http.StatusOK, `{"id":"100","name":"ping","type":1}`
+		case r.Method == http.MethodDelete && deleteFails:
+			return http.StatusForbidden, `{"code":50001,"message":"Missing Access"}`
+		case r.Method == http.MethodDelete:
+			return http.StatusNoContent, ""
+		}
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		return http.StatusNotFound, "{}"
+	})
+
+	cmd := NewSlash("ping").Description("Ping pong").Handler(func(ctx Context) error {
+		return nil
+	})
+	if err := k.RegisterCommands(cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	deleteFails = true
+	if err := k.UnregisterCommand("ping"); err == nil {
+		t.Fatal("expected unregistration to fail")
+	}
+	if len(k.cmds) != 1 || len(k.idcache) != 1 {
+		t.Fatalf("expected command to be kept, got %d registered and %d cached", len(k.cmds), len(k.idcache))
+	}
+
+	deleteFails = false
+	if err := k.UnregisterCommand("ping"); err != nil {
+		t.Fatal(err)
+	}
+	if len(k.cmds) != 0 || len(k.idcache) != 0 {
+		t.Fatalf("expected command to be removed, got %d registered and %d cached", len(k.cmds), len(k.idcache))
+	}
+}
+
+func TestCommandsAreDispatchedDuringSync(t *testing.T) {
+	var k *Ken
+	k = newTestRestKen(t, func(r *http.Request) (int, string) {
+		if r.Method == http.MethodGet {
+			found := make(chan bool, 1)
+			go func() {
+				found <- k.getCommand(discordgo.ChatApplicationCommand, "", "ping") != nil
+			}()
+			select {
+			case ok := <-found:
+				if !ok {
+					t.Error("expected command to be found")
+				}
+			case <-time.After(time.Second):
+				t.Error("command lookup blocked by sync")
+			}
+			return http.StatusOK, `[{"id": "100", "type": 1, "name": "ping", "description": "Ping pong"}]`
+		}
+		return http.StatusOK, `{"id":"100","name":"ping","type":1}`
+	})
+	k.appID = ""
+
+	cmd := NewSlash("ping").Description("Ping pong").Handler(func(ctx Context) error {
+		return nil
+	})
+	if err := k.RegisterCommands(cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	k.onReady(k.s, &discordgo.Ready{User: &discordgo.User{ID: "1"}})
+
+	if k.appID != "1" {
+		t.Fatalf("expected application ID to be set, got %q", k.appID)
+	}
+}
@@ -0,0 +1,178 @@
+package ken
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken/i18n"
+)
+
+// LocalizedCommand can be implemented by your commands
+// to provide localized names and descriptions which are
+// displayed to users depending on their client locale.
+type LocalizedCommand interface {
+	// NameLocalizations returns the localized names
+	// of the command by locale.
+	NameLocalizations() map[discordgo.Locale]string
+
+	// DescriptionLocalizations returns the localized
+	// descriptions of the command by locale.
+	DescriptionLocalizations() map[discordgo.Locale]string
+}
+
+// LocalizedOptionsCommand can be implemented by your slash
+// commands to provide localizations for their options and
+// choices.
+type LocalizedOptionsCommand interface {
+	// OptionLocalizations returns the localizations of
+	// options by their path. The path consists of the
+	// names of the option and its parent sub commands
+	// or sub command groups joined by dots (for example
+	// "group.sub.option").
+	OptionLocalizations() map[string]OptionLocalization
+}
+
+// OptionLocalization contains the localizations of a
+// single command option.
+type OptionLocalization struct {
+	// Name contains the localized names of the
+	// option by locale.
+	Name map[discordgo.Locale]string
+	// Description contains the localized descriptions
+	// of the option by locale.
+	Description map[discordgo.Locale]string
+	// Choices contains the localized names of the
+	// option choices by locale mapped by the choice
+	// names.
+	Choices map[string]map[discordgo.Locale]string
+}
+
+// localizeApplicationCommand sets the localizations of
+// the application command generated from cmd.
+//
+// Localizations provided by the command itself take
+// precedence over the ones taken from the catalog. The
+// catalog is looked up by keys in the following scheme.
+//
+//	commands.<command>.name
+//	commands.<command>.description
+//	commands.<command>.options.<option>.name
+//	commands.<command>.options.<option>.description
+//	commands.<command>.options.<option>.choices.<choice>
+//
+// Options of sub commands and sub command groups are
+// nested accordingly, for example
+// "commands.<command>.options.<sub>.options.<option>.name".
+func localizeApplicationCommand(
+	acmd *discordgo.ApplicationCommand,
+	cmd Command,
+	catalog *i18n.Catalog,
+) {
+	var names, descriptions map[discordgo.Locale]string
+	if lc, ok := cmd.(LocalizedCommand); ok {
+		names = lc.NameLocalizations()
+		descriptions = lc.DescriptionLocalizations()
+	}
+
+	prefix := "commands." + acmd.Name
+	names = mergeLocalizations(names, catalogLocalizations(catalog, prefix+".name"))
+	descriptions = mergeLocalizations(descriptions, catalogLocalizations(catalog, prefix+".description"))
+
+	if len(names) != 0 {
+		acmd.NameLocalizations = &names
+	}
+	if len(descriptions) != 0 && acmd.Type == discordgo.ChatApplicationCommand {
+		acmd.DescriptionLocalizations = &descriptions
+	}
+
+	if len(acmd.Options) == 0 {
+		return
+	}
+
+	var optLocs map[string]OptionLocalization
+	if loc, ok := cmd.(LocalizedOptionsCommand); ok {
+		optLocs = loc.OptionLocalizations()
+	}
+	if optLocs == nil && catalog == nil {
+		return
+	}
+
+	acmd.Options = copyOptions(acmd.Options)
+	localizeOptions(acmd.Options, "", prefix, optLocs, catalog)
+}
+
+func localizeOptions(
+	opts []*discordgo.ApplicationCommandOption,
+	path, prefix string,
+	optLocs map[string]OptionLocalization,
+	catalog *i18n.Catalog,
+) {
+	for _, opt := range opts {
+		optPath := opt.Name
+		if path != "" {
+			optPath = path + "." + opt.Name
+		}
+		optPrefix := prefix + ".options." + opt.Name
+		optLoc := optLocs[optPath]
+
+		opt.NameLocalizations = mergeLocalizations(opt.NameLocalizations, optLoc.Name)
+		opt.NameLocalizations = mergeLocalizations(opt.NameLocalizations,
+			catalogLocalizations(catalog, optPrefix+".name"))
+		opt.DescriptionLocalizations = mergeLocalizations(opt.DescriptionLocalizations, optLoc.Description)
+		opt.DescriptionLocalizations = mergeLocalizations(opt.DescriptionLocalizations,
+			catalogLocalizations(catalog, optPrefix+".description"))
+
+		for _, choice := range opt.Choices {
+			choice.NameLocalizations = mergeLocalizations(choice.NameLocalizations, optLoc.Choices[choice.Name])
+			choice.NameLocalizations = mergeLocalizations(choice.NameLocalizations,
+				catalogLocalizations(catalog, optPrefix+".choices."+choice.Name))
+		}
+
+		localizeOptions(opt.Options, optPath, optPrefix, optLocs, catalog)
+	}
+}
+
+func catalogLocalizations(catalog *i18n.Catalog, key string) map[discordgo.Locale]string {
+	if catalog == nil {
+		return nil
+	}
+	return catalog.Localizations(key)
+}
+
+// mergeLocalizations returns a map containing all
+// localizations of dst complemented by the ones of src
+// for locales which are not present in dst.
+func mergeLocalizations(dst, src map[discordgo.Locale]string) map[discordgo.Locale]string {
+	if len(src) == 0 {
+		return dst
+	}
+	merged := make(map[discordgo.Locale]string, len(dst)+len(src))
+	for locale, v := range src {
+		merged[locale] = v
+	}
+	for locale, v := range dst {
+		merged[locale] = v
+	}
+	return merged
+}
+
+// copyOptions returns a deep copy of the passed options
+// so that they can be modified without altering the
+// options returned by the command.
+func copyOptions(opts []*discordgo.ApplicationCommandOption) []*discordgo.ApplicationCommandOption {
+	if opts == nil {
+		return nil
+	}
+	cOpts := make([]*discordgo.ApplicationCommandOption, len(opts))
+	for i, opt := range opts {
+		cOpt := *opt
+		if opt.Choices != nil {
+			cOpt.Choices = make([]*discordgo.ApplicationCommandOptionChoice, len(opt.Choices))
+			for j, choice := range opt.Choices {
+				cChoice := *choice
+				cOpt.Choices[j] = &cChoice
+			}
+		}
+		cOpt.Options = copyOptions(opt.Options)
+		cOpts[i] = &cOpt
+	}
+	return cOpts
+}
@@ -0,0 +1,102 @@
+package ken
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken/i18n"
+)
+
+// Keys of the user-facing messages sent by Ken which can
+// be overridden in the MessageCatalog passed via Options.
+const (
+	// MessageErrorTitle is the title of error embeds
+	// which are sent without an explicit title.
+	MessageErrorTitle = "ken.error.title"
+	// MessageNotDMCapable is responded when a command
+	// which is not DM capable is executed in a DM.
+	MessageNotDMCapable = "ken.error.notdmcapable"
+	// MessageInternalError is responded when a handler
+	// panicked before responding to the interaction.
+	MessageInternalError = "ken.error.internal"
+	// MessageIncident is responded by the
+	// DefaultErrorResponder when a command returned an
+	// unexpected error. It is formatted with the
+	// incident ID.
+	MessageIncident = "ken.error.incident"
+)
+
+// defaultMessages contains the fallback messages which
+// are used when a message could not be found in the
+// MessageCatalog for any of the requested locales.
+var defaultMessages = i18n.NewCatalog().SetAll(discordgo.EnglishUS, map[string]string{
+	MessageErrorTitle:    "Error",
+	MessageNotDMCapable:  "This command can not be executed in DMs.",
+	MessageInternalError: "An unexpected error occurred. Please try again later.",
+	MessageIncident:      "An unexpected error occurred. Please try again later.\n\nIncident ID: `%s`",
+})
+
+// RegisterDefaultMessages registers the passed messages
+// by key as fallback messages for all instances of Ken.
+//
+// This should be used by middlewares to provide default
+// texts for their own messages, which then can be
+// translated in the MessageCatalog.
+func RegisterDefaultMessages(messages map[string]string) {
+	defaultMessages.SetAll(discordgo.EnglishUS, messages)
+}
+
+// T returns the message by key from the MessageCatalog
+// for the first of the passed locales for which a message
+// exists. When no locale matches, the DefaultLocale and
+// finally the registered default messages are used as
+// fallback. If args are passed, they are used to format
+// the message.
+//
+// When no message could be found at all, the key itself
+// is returned.
+func (k *Ken) T(locales []discordgo.Locale, key string, args ...interface{}) string {
+	locales = append(locales, k.opt.DefaultLocale)
+
+	msg, ok := lookupMessage(k.opt.MessageCatalog, locales, key)
+	if !ok {
+		msg, ok = lookupMessage(defaultMessages, locales, key)
+	}
+	if !ok {
+		msg, ok = defaultMessages.Get(discordgo.EnglishUS, key)
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) != 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+func lookupMessage(catalog *i18n.Catalog, locales []discordgo.Locale, key string) (string, bool) {
+	if catalog == nil {
+		return "", false
+	}
+	for _, locale := range locales {
+		if locale == discordgo.Unknown {
+			continue
+		}
+		if msg, ok := catalog.Get(locale, key); ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// interactionLocales returns the locale of the user which
+// created the interaction followed by the locale of the
+// guild the interaction has been created in, if available.
+func interactionLocales(i *discordgo.Interaction) []discordgo.Locale {
+	locales := []discordgo.Locale{i.Locale}
+	if i.GuildLocale != nil {
+		locales = append(locales, *i.GuildLocale)
+	}
+	return locales
+}
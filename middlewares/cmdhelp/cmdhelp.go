@@ -5,6 +5,25 @@ import (
 	"github.com/zekrotja/ken"
 )
 
+// Keys of the messages responded to help requests of
+// commands which do not implement HelpProvider. They
+// can be translated in the MessageCatalog of Ken.
+const (
+	// MessageNoHelp is responded when the command does not
+	// provide any help content.
+	MessageNoHelp = "ken.cmdhelp.nohelp"
+	// MessageNoHelpTitle is the title of the response sent
+	// when the command does not provide any help content.
+	MessageNoHelpTitle = "ken.cmdhelp.title"
+)
+
+func init() {
+	ken.RegisterDefaultMessages(map[string]string{
+		MessageNoHelp:      "There is no help available for this command.",
+		MessageNoHelpTitle: "Help",
+	})
+}
+
 // Middleware implements ken.MiddlewareBefore. It checks if a
 // command implements HelpProvider on execution and attaches
 // a help sub command handler. When the help sub command was
@@ -58,6 +77,10 @@ func (m *Middleware) subCmdHandler(cmd HelpProvider, executed chan<- bool) ken.S
 			if err != nil {
 				return
 			}
+			if emb == nil {
+				err = ctx.RespondError(ctx.T(MessageNoHelp), ctx.T(MessageNoHelpTitle))
+				return
+			}
 			err = ctx.Respond(&discordgo.InteractionResponse{
 				Type: discordgo.InteractionResponseChannelMessageWithSource,
 				Data: &discordgo.InteractionResponseData{
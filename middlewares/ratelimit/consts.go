@@ -0,0 +1,24 @@
+package ratelimit
+
+import "github.com/zekrotja/ken"
+
+// Keys of the error response sent when a user exceeds
+// the rate limit of a command. They are shared with the
+// v2 middleware, so a translation in the MessageCatalog
+// of Ken applies to both versions.
+const (
+	// MessageRateLimited is responded when a user is being
+	// rate limited. It is formatted with the duration until
+	// the command can be used again.
+	MessageRateLimited = "ken.ratelimit.message"
+	// MessageRateLimitedTitle is the title of the rate limit
+	// error response.
+	MessageRateLimitedTitle = "ken.ratelimit.title"
+)
+
+func init() {
+	ken.RegisterDefaultMessages(map[string]string{
+		MessageRateLimited:      "You are being ratelimited.\nWait %s until you can use this command again.",
+		MessageRateLimitedTitle: "Rate Limited",
+	})
+}
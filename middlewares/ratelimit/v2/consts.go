@@ -1,6 +1,16 @@
 package ratelimit
 
+import v1 "github.com/zekrotja/ken/middlewares/ratelimit"
+
 const (
 	limiterKey = "__middlewares/ratelimit/v2/limiter"
 	skipKey    = "skipratelimit"
 )
+
+// Keys of the rate limit error response. They are the
+// same keys as used by the v1 middleware, which registers
+// their default messages.
+const (
+	MessageRateLimited      = v1.MessageRateLimited
+	MessageRateLimitedTitle = v1.MessageRateLimitedTitle
+)
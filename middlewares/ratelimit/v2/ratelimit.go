@@ -1,7 +1,6 @@
 package ratelimit
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -65,9 +64,9 @@ func (m *Middleware) Before(ctx *ken.Ctx) (next bool, err error) {
 
 	limiter := m.manager.GetLimiter(ctx.Command, ctx.User().ID, guildID)
 	if ok, next := limiter.Take(); !ok {
-		err := ctx.RespondError(fmt.Sprintf(
-			"You are being ratelimited.\nWait %s until you can use this command again.",
-			next.Round(1*time.Second).String()), "Rate Limited")
+		err := ctx.RespondError(
+			ctx.T(MessageRateLimited, next.Round(1*time.Second).String()),
+			ctx.T(MessageRateLimitedTitle))
 		return false, err
 	}
 
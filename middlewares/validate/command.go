@@ -0,0 +1,13 @@
+package validate
+
+// ValidatedCommand defines a command which declares
+// constraints for the values of its options.
+type ValidatedCommand interface {
+	// Constraints returns the constraints which are
+	// checked before the command is executed.
+	//
+	// This is called on each command execution, so
+	// the constraints should be created once and
+	// then be returned.
+	Constraints() Constraints
+}
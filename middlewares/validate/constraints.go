@@ -0,0 +1,116 @@
+package validate
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken"
+)
+
+// Rule checks the value of a single option. If the
+// value is invalid, an error describing the violation
+// is returned, which is shown to the user.
+type Rule func(ctx ken.Context, opt *ken.CommandOption) error
+
+// Check checks multiple options of a command against
+// each other. The passed options are the top level
+// options of the command, so options of sub commands
+// are looked up by their path. If the options are
+// invalid, an error describing the violation is
+// returned, which is shown to the user.
+type Check func(ctx ken.Context, opts ken.CommandOptions) error
+
+// Constraints specifies the rules and checks which
+// are applied to the options of a command.
+type Constraints struct {
+	// Rules maps option paths to the rules which are
+	// applied to the value of the option. A path
+	// consists of the names of the sub command group,
+	// the sub command and the option separated by
+	// spaces, for example "config set channel".
+	//
+	// Rules of options which have not been passed
+	// are not applied.
+	Rules map[string][]Rule
+	// Checks are applied to all options after the
+	// rules of the single options.
+	Checks []Check
+}
+
+// Violation describes an invalid input.
+type Violation struct {
+	// Option is the path of the invalid option. It is
+	// empty for violations reported by checks.
+	Option string
+	// Message describes the violation.
+	Message string
+}
+
+var _ error = (*Violation)(nil)
+
+func (v *Violation) Error() string {
+	if v.Option == "" {
+		return v.Message
+	}
+	return v.Option + ": " + v.Message
+}
+
+// Violations is a list of violations which is
+// returned when validating options.
+type Violations []*Violation
+
+var _ error = (Violations)(nil)
+
+func (vs Violations) Error() string {
+	msgs := make([]string, 0, len(vs))
+	for _, v := range vs {
+		msgs = append(msgs, v.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate applies the rules and checks to the
+// passed options in the order of the options and
+// returns every violation found. If the options
+// are valid, nil is returned.
+func (c Constraints) Validate(ctx ken.Context, opts ken.CommandOptions) Violations {
+	var vs Violations
+
+	c.validateRules(ctx, opts, "", &vs)
+
+	for _, check := range c.Checks {
+		if err := check(ctx, opts); err != nil {
+			vs = append(vs, &Violation{Message: err.Error()})
+		}
+	}
+
+	if len(vs) == 0 {
+		return nil
+	}
+	return vs
+}
+
+func (c Constraints) validateRules(ctx ken.Context, opts ken.CommandOptions, parent string, vs *Violations) {
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		path := opt.Name
+		if parent != "" {
+			path = parent + " " + opt.Name
+		}
+
+		if opt.Type == discordgo.ApplicationCommandOptionSubCommand ||
+			opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+			c.validateRules(ctx, opt.Options, path, vs)
+			continue
+		}
+
+		for _, rule := range c.Rules[path] {
+			if err := rule(ctx, &ken.CommandOption{ApplicationCommandInteractionDataOption: opt}); err != nil {
+				*vs = append(*vs, &Violation{Option: path, Message: err.Error()})
+			}
+		}
+	}
+}
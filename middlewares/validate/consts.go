@@ -0,0 +1,63 @@
+package validate
+
+import "github.com/zekrotja/ken"
+
+// Keys of the response listing the violations of a
+// command input and of the violations reported by the
+// built-in rules. They can be translated in the
+// MessageCatalog of Ken.
+const (
+	// MessageInvalid is the header of the response listing
+	// the violations of the command input.
+	MessageInvalid = "ken.validate.message"
+	// MessageInvalidTitle is the title of the response
+	// listing the violations of the command input.
+	MessageInvalidTitle = "ken.validate.title"
+
+	// MessageNotString is reported when a rule for string
+	// values is applied to an option of another type.
+	MessageNotString = "ken.validate.notstring"
+	// MessageRegex is reported when a value does not match
+	// the required pattern.
+	MessageRegex = "ken.validate.regex"
+	// MessageURL is reported when a value is not a valid
+	// URL. It is formatted with the allowed hosts.
+	MessageURL = "ken.validate.url"
+	// MessageDate is reported when a value is not a valid
+	// date. It is formatted with the expected layout.
+	MessageDate = "ken.validate.date"
+	// MessageDateBefore is reported when a date is before
+	// the allowed minimum. It is formatted with the minimum.
+	MessageDateBefore = "ken.validate.datebefore"
+	// MessageDateAfter is reported when a date is after
+	// the allowed maximum. It is formatted with the maximum.
+	MessageDateAfter = "ken.validate.dateafter"
+	// MessageDateOrder is reported when the start date is
+	// after the end date. It is formatted with the names
+	// of both options.
+	MessageDateOrder = "ken.validate.dateorder"
+	// MessageExclusive is reported when mutually exclusive
+	// options are passed together. It is formatted with the
+	// names of the options.
+	MessageExclusive = "ken.validate.exclusive"
+	// MessageTogether is reported when options which must be
+	// passed together are passed partially. It is formatted
+	// with the names of the options.
+	MessageTogether = "ken.validate.together"
+)
+
+func init() {
+	ken.RegisterDefaultMessages(map[string]string{
+		MessageInvalid:      "The following input is invalid:",
+		MessageInvalidTitle: "Invalid Input",
+		MessageNotString:    "The value must be a text.",
+		MessageRegex:        "The value does not match the required format.",
+		MessageURL:          "The value must be a URL with one of the following hosts: %s",
+		MessageDate:         "The value must be a date in the format `%s`.",
+		MessageDateBefore:   "The date must not be before %s.",
+		MessageDateAfter:    "The date must not be after %s.",
+		MessageDateOrder:    "%s must not be after %s.",
+		MessageExclusive:    "The options %s can not be used together.",
+		MessageTogether:     "The options %s must be used together.",
+	})
+}
@@ -0,0 +1,186 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/zekrotja/ken"
+)
+
+// Regex returns a rule which checks that the value of
+// a string option matches the passed regular expression.
+//
+// It panics if the expression can not be compiled.
+func Regex(expr string) Rule {
+	rx := regexp.MustCompile(expr)
+	return func(ctx ken.Context, opt *ken.CommandOption) error {
+		v, err := stringValue(ctx, opt)
+		if err != nil {
+			return err
+		}
+		if !rx.MatchString(v) {
+			return errors.New(ctx.T(MessageRegex))
+		}
+		return nil
+	}
+}
+
+// URLHosts returns a rule which checks that the value
+// of a string option is an HTTP or HTTPS URL pointing
+// to one of the passed hosts.
+//
+// A host can start with a wildcard like "*.example.com"
+// to allow all sub domains of the host. When no hosts
+// are passed, all hosts are allowed.
+func URLHosts(hosts ...string) Rule {
+	return func(ctx ken.Context, opt *ken.CommandOption) error {
+		v, err := stringValue(ctx, opt)
+		if err != nil {
+			return err
+		}
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Hostname() == "" || !hostAllowed(u.Hostname(), hosts) {
+			allowed := "*"
+			if len(hosts) != 0 {
+				allowed = strings.Join(hosts, ", ")
+			}
+			return errors.New(ctx.T(MessageURL, allowed))
+		}
+		return nil
+	}
+}
+
+// DateRange returns a rule which checks that the value
+// of a string option is a date in the passed layout
+// which is neither before min nor after max.
+//
+// Zero values for min or max do not limit the range
+// in the respective direction.
+func DateRange(layout string, min, max time.Time) Rule {
+	return func(ctx ken.Context, opt *ken.CommandOption) error {
+		v, err := stringValue(ctx, opt)
+		if err != nil {
+			return err
+		}
+		t, err := time.Parse(layout, v)
+		if err != nil {
+			return errors.New(ctx.T(MessageDate, layout))
+		}
+		if !min.IsZero() && t.Before(min) {
+			return errors.New(ctx.T(MessageDateBefore, min.Format(layout)))
+		}
+		if !max.IsZero() && t.After(max) {
+			return errors.New(ctx.T(MessageDateAfter, max.Format(layout)))
+		}
+		return nil
+	}
+}
+
+// DateOrder returns a check which ensures that the
+// date of the option at the start path is not after
+// the date of the option at the end path.
+//
+// The check is skipped if one of the options has not
+// been passed or is not a date in the passed layout.
+// Use DateRange to validate the format of the dates.
+func DateOrder(layout, start, end string) Check {
+	return func(ctx ken.Context, opts ken.CommandOptions) error {
+		startDate, okStart := dateValue(opts, start, layout)
+		endDate, okEnd := dateValue(opts, end, layout)
+		if !okStart || !okEnd || !startDate.After(endDate) {
+			return nil
+		}
+		return errors.New(ctx.T(MessageDateOrder, optionName(start), optionName(end)))
+	}
+}
+
+// MutuallyExclusive returns a check which ensures that
+// at most one of the options at the passed paths has
+// been passed.
+func MutuallyExclusive(paths ...string) Check {
+	return func(ctx ken.Context, opts ken.CommandOptions) error {
+		if countPassed(opts, paths) > 1 {
+			return errors.New(ctx.T(MessageExclusive, optionNames(paths)))
+		}
+		return nil
+	}
+}
+
+// RequiredTogether returns a check which ensures that
+// either all or none of the options at the passed paths
+// have been passed.
+func RequiredTogether(paths ...string) Check {
+	return func(ctx ken.Context, opts ken.CommandOptions) error {
+		if n := countPassed(opts, paths); n != 0 && n != len(paths) {
+			return errors.New(ctx.T(MessageTogether, optionNames(paths)))
+		}
+		return nil
+	}
+}
+
+func stringValue(ctx ken.Context, opt *ken.CommandOption) (string, error) {
+	v, ok := opt.Value.(string)
+	if !ok {
+		return "", errors.New(ctx.T(MessageNotString))
+	}
+	return v, nil
+}
+
+func hostAllowed(host string, hosts []string) bool {
+	if len(hosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, h := range hosts {
+		h = strings.ToLower(h)
+		if strings.HasPrefix(h, "*.") {
+			if strings.HasSuffix(host, h[1:]) {
+				return true
+			}
+			continue
+		}
+		if host == h {
+			return true
+		}
+	}
+	return false
+}
+
+func dateValue(opts ken.CommandOptions, path, layout string) (t time.Time, ok bool) {
+	v, err := opts.StringOpt(path)
+	if err != nil {
+		return
+	}
+	t, err = time.Parse(layout, v)
+	return t, err == nil
+}
+
+func countPassed(opts ken.CommandOptions, paths []string) (n int) {
+	for _, path := range paths {
+		if _, err := opts.Find(path); err == nil {
+			n++
+		}
+	}
+	return
+}
+
+func optionName(path string) string {
+	names := strings.Fields(path)
+	if len(names) == 0 {
+		return "``"
+	}
+	return fmt.Sprintf("`%s`", names[len(names)-1])
+}
+
+func optionNames(paths []string) string {
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, optionName(path))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,202 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken"
+)
+
+// kenContext is embedded by testContext under another
+// name, because ken.Context has a method called Context.
+type kenContext = ken.Context
+
+// testContext returns message keys untranslated, so
+// that violations can be compared by their key.
+type testContext struct {
+	kenContext
+}
+
+func (testContext) T(key string, args ...interface{}) string {
+	return key
+}
+
+func stringOpt(name string, v interface{}) *discordgo.ApplicationCommandInteractionDataOption {
+	return &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  name,
+		Type:  discordgo.ApplicationCommandOptionString,
+		Value: v,
+	}
+}
+
+func TestRules(t *testing.T) {
+	date := func(v string) time.Time {
+		d, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	cases := []struct {
+		name  string
+		rule  Rule
+		value interface{}
+		want  string
+	}{
+		{name: "regex match", rule: Regex(`^[a-z]+$`), value: "abc"},
+		{name: "regex mismatch", rule: Regex(`^[a-z]+$`), value: "ABC", want: MessageRegex},
+		{name: "regex on number", rule: Regex(`^[0-9]+$`), value: float64(1), want: MessageNotString},
+		{name: "url of any host", rule: URLHosts(), value: "https://example.com/path"},
+		{name: "url of allowed host", rule: URLHosts("example.com"), value: "http://EXAMPLE.com"},
+		{name: "url of sub domain", rule: URLHosts("*.example.com"), value: "https://a.b.example.com"},
+		{name: "url of wildcard parent", rule: URLHosts("*.example.com"), value: "https://example.com", want: MessageURL},
+		{name: "url of other host", rule: URLHosts("example.com"), value: "https://example.org", want: MessageURL},
+		{name: "url of suffixed host", rule: URLHosts("*.example.com"), value: "https://badexample.com", want: MessageURL},
+		{name: "url without http", rule: URLHosts(), value: "ftp://example.com", want: MessageURL},
+		{name: "url without host", rule: URLHosts(), value: "https://", want: MessageURL},
+		{name: "no url", rule: URLHosts(), value: "example", want: MessageURL},
+		{
+			name:  "date in range",
+			rule:  DateRange("2006-01-02", date("2024-01-01"), date("2024-12-31")),
+			value: "2024-06-01",
+		},
+		{
+			name:  "date on bounds",
+			rule:  DateRange("2006-01-02", date("2024-01-01"), date("2024-01-01")),
+			value: "2024-01-01",
+		},
+		{
+			name:  "date without bounds",
+			rule:  DateRange("2006-01-02", time.Time{}, time.Time{}),
+			value: "1900-01-01",
+		},
+		{
+			name:  "date before min",
+			rule:  DateRange("2006-01-02", date("2024-01-01"), time.Time{}),
+			value: "2023-12-31",
+			want:  MessageDateBefore,
+		},
+		{
+			name:  "date after max",
+			rule:  DateRange("2006-01-02", time.Time{}, date("2024-12-31")),
+			value: "2025-01-01",
+			want:  MessageDateAfter,
+		},
+		{
+			name:  "date in other layout",
+			rule:  DateRange("2006-01-02", time.Time{}, time.Time{}),
+			value: "01.06.2024",
+			want:  MessageDate,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.rule(testContext{}, &ken.CommandOption{
+				ApplicationCommandInteractionDataOption: stringOpt("value", c.value),
+			})
+			var got string
+			if err != nil {
+				got = err.Error()
+			}
+			if got != c.want {
+				t.Errorf("expected violation %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestChecks(t *testing.T) {
+	opts := ken.CommandOptions{
+		stringOpt("start", "2024-02-01"),
+		stringOpt("end", "2024-01-01"),
+		stringOpt("invalid", "tomorrow"),
+		{
+			Name: "sub",
+			Type: discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				stringOpt("a", "a"),
+				stringOpt("b", "b"),
+			},
+		},
+	}
+
+	cases := []struct {
+		name  string
+		check Check
+		want  string
+	}{
+		{name: "dates in order", check: DateOrder("2006-01-02", "end", "start")},
+		{name: "dates out of order", check: DateOrder("2006-01-02", "start", "end"), want: MessageDateOrder},
+		{name: "invalid date", check: DateOrder("2006-01-02", "invalid", "end")},
+		{name: "missing date", check: DateOrder("2006-01-02", "start", "missing")},
+		{name: "one exclusive option", check: MutuallyExclusive("start", "missing")},
+		{name: "no exclusive option", check: MutuallyExclusive("missing", "other")},
+		{name: "exclusive options", check: MutuallyExclusive("missing", "sub a", "sub b"), want: MessageExclusive},
+		{name: "all options together", check: RequiredTogether("sub a", "sub b")},
+		{name: "no options together", check: RequiredTogether("missing", "other")},
+		{name: "partial options together", check: RequiredTogether("sub a", "sub c"), want: MessageTogether},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.check(testContext{}, opts)
+			var got string
+			if err != nil {
+				got = err.Error()
+			}
+			if got != c.want {
+				t.Errorf("expected violation %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestConstraintsValidate(t *testing.T) {
+	c := Constraints{
+		Rules: map[string][]Rule{
+			"name":               {Regex(`^[a-z]+$`)},
+			"config set url":     {URLHosts("example.com"), Regex(`^https://`)},
+			"config set missing": {Regex(`^$`)},
+			"url":                {URLHosts("example.com")},
+		},
+		Checks: []Check{
+			MutuallyExclusive("name", "config set url"),
+		},
+	}
+
+	opts := ken.CommandOptions{
+		stringOpt("name", "Name"),
+		{
+			Name: "config",
+			Type: discordgo.ApplicationCommandOptionSubCommandGroup,
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{
+					Name: "set",
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						stringOpt("url", "http://example.org"),
+					},
+				},
+			},
+		},
+	}
+
+	want := Violations{
+		{Option: "name", Message: MessageRegex},
+		{Option: "config set url", Message: MessageURL},
+		{Option: "config set url", Message: MessageRegex},
+		{Message: MessageExclusive},
+	}
+	if got := c.Validate(testContext{}, opts); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected violations %q, got %q", want, got)
+	}
+
+	valid := ken.CommandOptions{stringOpt("name", "name")}
+	if got := c.Validate(testContext{}, valid); got != nil {
+		t.Errorf("expected no violations, got %q", got)
+	}
+}
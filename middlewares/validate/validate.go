@@ -0,0 +1,53 @@
+package validate
+
+import (
+	"strings"
+
+	"github.com/zekrotja/ken"
+)
+
+// Middleware implements ken.MiddlewareBefore. It checks if
+// a command implements ValidatedCommand on execution and
+// validates the passed options against the constraints of
+// the command. If the options are invalid, an ephemeral
+// error message listing every violation is responded and
+// the command is not executed.
+type Middleware struct{}
+
+var _ ken.MiddlewareBefore = (*Middleware)(nil)
+
+// New returns a new instance of Middleware.
+func New() *Middleware {
+	return &Middleware{}
+}
+
+func (m *Middleware) Before(ctx *ken.Ctx) (next bool, err error) {
+	cmd, ok := ctx.Command.(ValidatedCommand)
+	if !ok {
+		return true, nil
+	}
+
+	vs := cmd.Constraints().Validate(ctx, ctx.Options())
+	if len(vs) == 0 {
+		return true, nil
+	}
+
+	ctx.SetEphemeral(true)
+	err = ctx.RespondError(formatViolations(ctx, vs), ctx.T(MessageInvalidTitle))
+	return false, err
+}
+
+func formatViolations(ctx ken.Context, vs Violations) string {
+	var sb strings.Builder
+	sb.WriteString(ctx.T(MessageInvalid))
+	for _, v := range vs {
+		sb.WriteString("\n- ")
+		if v.Option != "" {
+			sb.WriteString("**")
+			sb.WriteString(optionName(v.Option))
+			sb.WriteString("**: ")
+		}
+		sb.WriteString(v.Message)
+	}
+	return sb.String()
+}
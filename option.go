@@ -0,0 +1,262 @@
+package ken
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken/i18n"
+	"github.com/zekrotja/ken/state"
+	"github.com/zekrotja/ken/store"
+)
+
+// Option can be passed to New to configure an
+// instance of Ken.
+//
+// It is implemented by Options as well as by the
+// functional options returned by the With functions.
+type Option interface {
+	apply(o *Options) error
+}
+
+var _ Option = Options{}
+
+// apply applies all non-zero fields of o to opt.
+func (o Options) apply(opt *Options) error {
+	if o.State != nil {
+		opt.State = o.State
+	}
+	if o.CommandStore != nil {
+		opt.CommandStore = o.CommandStore
+	}
+	if o.DependencyProvider != nil {
+		opt.DependencyProvider = o.DependencyProvider
+	}
+	if o.CommandCatalog != nil {
+		opt.CommandCatalog = o.CommandCatalog
+	}
+	if o.MessageCatalog != nil {
+		opt.MessageCatalog = o.MessageCatalog
+	}
+	if o.DefaultLocale != discordgo.Unknown {
+		opt.DefaultLocale = o.DefaultLocale
+	}
+	if o.DisableCommandInfoCache {
+		opt.DisableCommandInfoCache = true
+	}
+	if o.OnSystemError != nil {
+		opt.OnSystemError = o.OnSystemError
+	}
+	if o.OnCommandError != nil {
+		opt.OnCommandError = o.OnCommandError
+	}
+	if o.ErrorResponder != nil {
+		opt.ErrorResponder = o.ErrorResponder
+	}
+	if o.OnEventError != nil {
+		opt.OnEventError = o.OnEventError
+	}
+	if o.OnPanic != nil {
+		opt.OnPanic = o.OnPanic
+	}
+	if o.EmbedColors.Default != 0 {
+		opt.EmbedColors.Default = o.EmbedColors.Default
+	}
+	if o.EmbedColors.Error != 0 {
+		opt.EmbedColors.Error = o.EmbedColors.Error
+	}
+	return nil
+}
+
+// validate checks the resulting options after all
+// passed options have been applied, including the
+// combinations of options which depend on each other.
+func (o *Options) validate() error {
+	if _, ok := discordgo.Locales[o.DefaultLocale]; !ok {
+		return invalidOption("unknown default locale %q", o.DefaultLocale)
+	}
+	if o.EmbedColors.Default < 0 || o.EmbedColors.Error < 0 {
+		return invalidOption("embed colors must not be negative")
+	}
+
+	// The built-in messages are English, so any other
+	// default locale must be provided by the catalog.
+	if o.MessageCatalog != nil && o.DefaultLocale != discordgo.EnglishUS &&
+		!containsLocale(o.MessageCatalog.Locales(), o.DefaultLocale) {
+		return invalidOption("default locale %q is not contained in the message catalog", o.DefaultLocale)
+	}
+
+	// The localizations of the command catalog are sent
+	// to Discord, which rejects unknown locales.
+	if o.CommandCatalog != nil {
+		for _, locale := range o.CommandCatalog.Locales() {
+			if _, ok := discordgo.Locales[locale]; !ok {
+				return invalidOption("unknown locale %q in command catalog", locale)
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsLocale(locales []discordgo.Locale, locale discordgo.Locale) bool {
+	for _, l := range locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+type optionFunc func(o *Options) error
+
+func (f optionFunc) apply(o *Options) error {
+	return f(o)
+}
+
+// WithState sets the state manager to be used.
+func WithState(st state.State) Option {
+	return optionFunc(func(o *Options) error {
+		if st == nil {
+			return invalidOption("state must not be nil")
+		}
+		o.State = st
+		return nil
+	})
+}
+
+// WithStore sets the storage instance to cache
+// created commands. Passing nil disables caching.
+func WithStore(cs store.CommandStore) Option {
+	return optionFunc(func(o *Options) error {
+		o.CommandStore = cs
+		return nil
+	})
+}
+
+// WithDependencyProvider sets the provider used to
+// look up dependencies in the Ctx.
+func WithDependencyProvider(dp ObjectProvider) Option {
+	return optionFunc(func(o *Options) error {
+		o.DependencyProvider = dp
+		return nil
+	})
+}
+
+// WithEmbedColors sets the colors of embeds. Zero
+// values keep the default colors.
+func WithEmbedColors(colors EmbedColors) Option {
+	return optionFunc(func(o *Options) error {
+		if colors.Default < 0 || colors.Error < 0 {
+			return invalidOption("embed colors must not be negative")
+		}
+		if colors.Default > 0 {
+			o.EmbedColors.Default = colors.Default
+		}
+		if colors.Error > 0 {
+			o.EmbedColors.Error = colors.Error
+		}
+		return nil
+	})
+}
+
+// WithCommandCatalog sets the catalog used to
+// localize registered commands.
+func WithCommandCatalog(c *i18n.Catalog) Option {
+	return optionFunc(func(o *Options) error {
+		o.CommandCatalog = c
+		return nil
+	})
+}
+
+// WithMessageCatalog sets the catalog used to look
+// up user-facing messages.
+func WithMessageCatalog(c *i18n.Catalog) Option {
+	return optionFunc(func(o *Options) error {
+		o.MessageCatalog = c
+		return nil
+	})
+}
+
+// WithDefaultLocale sets the locale used as fallback
+// to look up messages.
+func WithDefaultLocale(locale discordgo.Locale) Option {
+	return optionFunc(func(o *Options) error {
+		if _, ok := discordgo.Locales[locale]; !ok {
+			return invalidOption("unknown default locale %q", locale)
+		}
+		o.DefaultLocale = locale
+		return nil
+	})
+}
+
+// WithCommandInfoCacheDisabled disables caching the
+// result of Ken#GetCommandInfo.
+func WithCommandInfoCacheDisabled() Option {
+	return optionFunc(func(o *Options) error {
+		o.DisableCommandInfoCache = true
+		return nil
+	})
+}
+
+// WithOnSystemError sets the handler for recoverable
+// system errors.
+func WithOnSystemError(f func(context string, err error, args ...interface{})) Option {
+	return optionFunc(func(o *Options) error {
+		if f == nil {
+			return invalidOption("OnSystemError must not be nil")
+		}
+		o.OnSystemError = f
+		return nil
+	})
+}
+
+// WithOnCommandError sets the handler for errors
+// occurring during middleware or command execution.
+func WithOnCommandError(f func(err error, ctx *Ctx)) Option {
+	return optionFunc(func(o *Options) error {
+		if f == nil {
+			return invalidOption("OnCommandError must not be nil")
+		}
+		o.OnCommandError = f
+		return nil
+	})
+}
+
+// WithErrorResponder sets the function responding
+// errors of commands and middlewares to the user.
+func WithErrorResponder(f func(err error, ctx *Ctx) error) Option {
+	return optionFunc(func(o *Options) error {
+		if f == nil {
+			return invalidOption("ErrorResponder must not be nil")
+		}
+		o.ErrorResponder = f
+		return nil
+	})
+}
+
+// WithOnEventError sets the handler for other user
+// event based errors.
+func WithOnEventError(f func(context string, err error)) Option {
+	return optionFunc(func(o *Options) error {
+		if f == nil {
+			return invalidOption("OnEventError must not be nil")
+		}
+		o.OnEventError = f
+		return nil
+	})
+}
+
+// WithOnPanic sets the handler for recovered panics.
+func WithOnPanic(f func(p *Panic)) Option {
+	return optionFunc(func(o *Options) error {
+		if f == nil {
+			return invalidOption("OnPanic must not be nil")
+		}
+		o.OnPanic = f
+		return nil
+	})
+}
+
+func invalidOption(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidOption, fmt.Sprintf(format, args...))
+}
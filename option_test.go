@@ -0,0 +1,78 @@
+package ken
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken/i18n"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	germanMessages := i18n.NewCatalog().Set(discordgo.German, MessageInternalError, "Interner Fehler")
+
+	cases := []struct {
+		name    string
+		options []Option
+		valid   bool
+	}{
+		{
+			name:  "defaults",
+			valid: true,
+		},
+		{
+			name:    "unknown default locale",
+			options: []Option{Options{DefaultLocale: "xx"}},
+		},
+		{
+			name:    "negative embed color",
+			options: []Option{Options{EmbedColors: EmbedColors{Error: -1}}},
+		},
+		{
+			name:    "default locale without message catalog",
+			options: []Option{WithDefaultLocale(discordgo.German)},
+			valid:   true,
+		},
+		{
+			name:    "default locale in message catalog",
+			options: []Option{WithMessageCatalog(germanMessages), WithDefaultLocale(discordgo.German)},
+			valid:   true,
+		},
+		{
+			name:    "default locale missing in message catalog",
+			options: []Option{WithMessageCatalog(germanMessages), WithDefaultLocale(discordgo.French)},
+		},
+		{
+			name:    "english default locale missing in message catalog",
+			options: []Option{WithMessageCatalog(germanMessages)},
+			valid:   true,
+		},
+		{
+			name:    "command catalog with known locales",
+			options: []Option{WithCommandCatalog(i18n.NewCatalog().Set(discordgo.German, "ping.description", "Ping"))},
+			valid:   true,
+		},
+		{
+			name:    "command catalog with unknown locale",
+			options: []Option{WithCommandCatalog(i18n.NewCatalog().Set("de_DE", "ping.description", "Ping"))},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			k, err := New(&discordgo.Session{State: discordgo.NewState()}, c.options...)
+			if c.valid {
+				if err != nil {
+					t.Fatalf("expected options to be valid, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidOption) {
+				t.Fatalf("expected ErrInvalidOption, got %v", err)
+			}
+			if k != nil {
+				t.Fatal("expected no instance to be returned")
+			}
+		})
+	}
+}
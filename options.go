@@ -1,6 +1,7 @@
 package ken
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
@@ -59,65 +60,81 @@ type CommandOption struct {
 
 // ChannelValue is a utility function for casting option value to channel object.
 //
-// The object is taken from the specified state instance.
+// The object is taken from the resolved data of the interaction. If it
+// has not been resolved, it is taken from the specified state instance.
 func (o *CommandOption) ChannelValue(ctx Context) *discordgo.Channel {
 	if o.Type != discordgo.ApplicationCommandOptionChannel {
 		panic("ChannelValue called on data option of type " + o.Type.String())
 	}
 	chanID := o.Value.(string)
 
-	if ctx == nil {
-		return &discordgo.Channel{ID: chanID}
-	}
-
-	ch, err := ctx.GetKen().opt.State.Channel(ctx.GetSession(), chanID)
-	if err != nil {
-		return &discordgo.Channel{ID: chanID}
-	}
-
-	return ch
+	return resolveChannel(ctx, chanID)
 }
 
 // RoleValue is a utility function for casting option value to role object.
 //
-// The object is taken from the specified state instance.
+// The object is taken from the resolved data of the interaction. If it
+// has not been resolved, it is taken from the specified state instance.
 func (o *CommandOption) RoleValue(ctx Context) *discordgo.Role {
 	if o.Type != discordgo.ApplicationCommandOptionRole {
 		panic("RoleValue called on data option of type " + o.Type.String())
 	}
 	roleID := o.Value.(string)
 
-	if ctx == nil {
-		return &discordgo.Role{ID: roleID}
-	}
-
-	role, err := ctx.GetKen().opt.State.Role(ctx.GetSession(), ctx.GetEvent().GuildID, roleID)
-	if err != nil {
-		return &discordgo.Role{ID: roleID}
-	}
-
-	return role
+	return resolveRole(ctx, roleID)
 }
 
 // UserValue is a utility function for casting option value to user object.
 //
-// The object is taken from the specified state instance.
+// The object is taken from the resolved data of the interaction. If it
+// has not been resolved, it is taken from the specified state instance.
 func (o *CommandOption) UserValue(ctx Context) *discordgo.User {
 	if o.Type != discordgo.ApplicationCommandOptionUser {
 		panic("UserValue called on data option of type " + o.Type.String())
 	}
 	userID := o.Value.(string)
 
+	return resolveUser(ctx, userID)
+}
+
+// MemberValue is a utility function for casting option value to guild
+// member object.
+//
+// The object is taken from the resolved data of the interaction and
+// contains the permissions of the member in the channel the command has
+// been executed in. If the member has not been resolved, for example
+// because the command has been executed in a DM, nil is returned.
+func (o *CommandOption) MemberValue(ctx Context) *discordgo.Member {
+	if o.Type != discordgo.ApplicationCommandOptionUser &&
+		o.Type != discordgo.ApplicationCommandOptionMentionable {
+		panic("MemberValue called on data option of type " + o.Type.String())
+	}
+	userID := o.Value.(string)
+
+	member, _ := resolveMember(ctx, userID)
+	return member
+}
+
+// AttachmentValue is a utility function for casting option value to
+// attachment object.
+//
+// The object is taken from the resolved data of the interaction.
+func (o *CommandOption) AttachmentValue(ctx Context) *discordgo.MessageAttachment {
+	if o.Type != discordgo.ApplicationCommandOptionAttachment {
+		panic("AttachmentValue called on data option of type " + o.Type.String())
+	}
+	attachmentID := o.Value.(string)
+
 	if ctx == nil {
-		return &discordgo.User{ID: userID}
+		return &discordgo.MessageAttachment{ID: attachmentID}
 	}
 
-	user, err := ctx.GetKen().opt.State.User(ctx.GetSession(), userID)
-	if err != nil {
-		return &discordgo.User{ID: userID}
+	attachment, ok := resolvedData(ctx).Attachments[attachmentID]
+	if !ok {
+		return &discordgo.MessageAttachment{ID: attachmentID}
 	}
 
-	return user
+	return attachment
 }
 
 // StringValue is a utility function for casting option value to string.
@@ -129,3 +146,434 @@ func (o *CommandOption) StringValue() (v string) {
 	v = strings.ReplaceAll(v, "\\n", "\n")
 	return
 }
+
+// Mentionable is the value of a mentionable option,
+// which is either a user or a role.
+type Mentionable struct {
+	// ID is the ID of the mentioned user or role.
+	ID string
+	// User is the mentioned user, if a user has
+	// been mentioned.
+	User *discordgo.User
+	// Member is the mentioned member, if a user has
+	// been mentioned in a guild.
+	Member *discordgo.Member
+	// Role is the mentioned role, if a role has been
+	// mentioned.
+	Role *discordgo.Role
+}
+
+// Find returns an option by path. The path consists of
+// the names of the sub command group, the sub command
+// and the option separated by spaces, for example
+// "config set channel". When the path contains only a
+// single name, the option is looked up on the current
+// level.
+//
+// If the option could not be found, ErrOptionNotFound
+// is returned.
+func (co CommandOptions) Find(path string) (opt *CommandOption, err error) {
+	names := strings.Fields(path)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: empty path", ErrOptionNotFound)
+	}
+
+	opts := co
+	for i, name := range names {
+		var ok bool
+		opt, ok = opts.GetByNameOptional(name)
+		if !ok || opt.ApplicationCommandInteractionDataOption == nil {
+			return nil, fmt.Errorf("%w: %s", ErrOptionNotFound, strings.Join(names[:i+1], " "))
+		}
+		if i == len(names)-1 {
+			break
+		}
+		if opt.Type != discordgo.ApplicationCommandOptionSubCommand &&
+			opt.Type != discordgo.ApplicationCommandOptionSubCommandGroup {
+			return nil, fmt.Errorf("%w: %s", ErrOptionNotFound, path)
+		}
+		opts = opt.Options
+	}
+
+	return
+}
+
+// StringOpt returns the value of the string option by
+// path. Escaped line breaks are converted to actual
+// line breaks.
+//
+// It is not called String, so that CommandOptions is
+// not mistaken for a fmt.Stringer.
+func (co CommandOptions) StringOpt(path string) (v string, err error) {
+	opt, err := co.findTyped(path, discordgo.ApplicationCommandOptionString)
+	if err != nil {
+		return
+	}
+	v, ok := opt.Value.(string)
+	if !ok {
+		return "", opt.valueTypeMismatch()
+	}
+	v = strings.ReplaceAll(v, "\\n", "\n")
+	return
+}
+
+// StringOr returns the value of the string option by
+// path or def, if it could not be retrieved.
+func (co CommandOptions) StringOr(path string, def string) string {
+	v, err := co.StringOpt(path)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Int returns the value of the integer option by path.
+func (co CommandOptions) Int(path string) (v int64, err error) {
+	opt, err := co.findTyped(path, discordgo.ApplicationCommandOptionInteger)
+	if err != nil {
+		return
+	}
+	if !isNumber(opt.Value) {
+		return 0, opt.valueTypeMismatch()
+	}
+	return int64(toFloat64(opt.Value)), nil
+}
+
+// IntOr returns the value of the integer option by
+// path or def, if it could not be retrieved.
+func (co CommandOptions) IntOr(path string, def int64) int64 {
+	v, err := co.Int(path)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Float returns the value of the number option by path.
+func (co CommandOptions) Float(path string) (v float64, err error) {
+	opt, err := co.findTyped(path, discordgo.ApplicationCommandOptionNumber)
+	if err != nil {
+		return
+	}
+	if !isNumber(opt.Value) {
+		return 0, opt.valueTypeMismatch()
+	}
+	return toFloat64(opt.Value), nil
+}
+
+// FloatOr returns the value of the number option by
+// path or def, if it could not be retrieved.
+func (co CommandOptions) FloatOr(path string, def float64) float64 {
+	v, err := co.Float(path)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Bool returns the value of the boolean option by path.
+func (co CommandOptions) Bool(path string) (v bool, err error) {
+	opt, err := co.findTyped(path, discordgo.ApplicationCommandOptionBoolean)
+	if err != nil {
+		return
+	}
+	v, ok := opt.Value.(bool)
+	if !ok {
+		return false, opt.valueTypeMismatch()
+	}
+	return
+}
+
+// BoolOr returns the value of the boolean option by
+// path or def, if it could not be retrieved.
+func (co CommandOptions) BoolOr(path string, def bool) bool {
+	v, err := co.Bool(path)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// User returns the user of the user option by path.
+//
+// The user is taken from the resolved data of the
+// interaction in ctx. If not available, it is taken
+// from the specified state instance. If this fails as
+// well, a user object containing only the ID is
+// returned.
+func (co CommandOptions) User(ctx Context, path string) (v *discordgo.User, err error) {
+	id, err := co.idValue(path, discordgo.ApplicationCommandOptionUser)
+	if err != nil {
+		return
+	}
+	return resolveUser(ctx, id), nil
+}
+
+// UserOr returns the user of the user option by path
+// or def, if it could not be retrieved.
+func (co CommandOptions) UserOr(ctx Context, path string, def *discordgo.User) *discordgo.User {
+	v, err := co.User(ctx, path)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Member returns the guild member of the user option
+// by path from the resolved data of the interaction in
+// ctx. If the member has not been resolved, for example
+// because the command has been executed in a DM,
+// ErrOptionNotResolved is returned.
+func (co CommandOptions) Member(ctx Context, path string) (v *discordgo.Member, err error) {
+	id, err := co.idValue(path, discordgo.ApplicationCommandOptionUser)
+	if err != nil {
+		return
+	}
+	v, ok := resolveMember(ctx, id)
+	if !ok {
+		return nil, fmt.Errorf("%w: member %s", ErrOptionNotResolved, id)
+	}
+	return
+}
+
+// MemberOr returns the guild member of the user option
+// by path or def, if it could not be retrieved.
+func (co CommandOptions) MemberOr(ctx Context, path string, def *discordgo.Member) *discordgo.Member {
+	v, err := co.Member(ctx, path)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Role returns the role of the role option by path.
+//
+// The role is taken from the resolved data of the
+// interaction in ctx. If not available, it is taken
+// from the specified state instance. If this fails as
+// well, a role object containing only the ID is
+// returned.
+func (co CommandOptions) Role(ctx Context, path string) (v *discordgo.Role, err error) {
+	id, err := co.idValue(path, discordgo.ApplicationCommandOptionRole)
+	if err != nil {
+		return
+	}
+	return resolveRole(ctx, id), nil
+}
+
+// RoleOr returns the role of the role option by path
+// or def, if it could not be retrieved.
+func (co CommandOptions) RoleOr(ctx Context, path string, def *discordgo.Role) *discordgo.Role {
+	v, err := co.Role(ctx, path)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Channel returns the channel of the channel option
+// by path.
+//
+// The channel is taken from the resolved data of the
+// interaction in ctx. If not available, it is taken
+// from the specified state instance. If this fails as
+// well, a channel object containing only the ID is
+// returned.
+func (co CommandOptions) Channel(ctx Context, path string) (v *discordgo.Channel, err error) {
+	id, err := co.idValue(path, discordgo.ApplicationCommandOptionChannel)
+	if err != nil {
+		return
+	}
+	return resolveChannel(ctx, id), nil
+}
+
+// ChannelOr returns the channel of the channel option
+// by path or def, if it could not be retrieved.
+func (co CommandOptions) ChannelOr(ctx Context, path string, def *discordgo.Channel) *discordgo.Channel {
+	v, err := co.Channel(ctx, path)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Mentionable returns the mentioned user or role of the
+// mentionable option by path. Which of both has been
+// mentioned is determined by the resolved data of the
+// interaction in ctx.
+//
+// If neither could be resolved, ErrOptionNotResolved
+// is returned.
+func (co CommandOptions) Mentionable(ctx Context, path string) (v *Mentionable, err error) {
+	id, err := co.idValue(path, discordgo.ApplicationCommandOptionMentionable)
+	if err != nil {
+		return
+	}
+
+	resolved := resolvedData(ctx)
+	v = &Mentionable{ID: id}
+	if user, ok := resolved.Users[id]; ok {
+		v.User = user
+		v.Member, _ = resolveMember(ctx, id)
+	} else if role, ok := resolved.Roles[id]; ok {
+		v.Role = role
+	} else {
+		return nil, fmt.Errorf("%w: mentionable %s", ErrOptionNotResolved, id)
+	}
+	return
+}
+
+// MentionableOr returns the mentioned user or role of
+// the mentionable option by path or def, if it could
+// not be retrieved.
+func (co CommandOptions) MentionableOr(ctx Context, path string, def *Mentionable) *Mentionable {
+	v, err := co.Mentionable(ctx, path)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Attachment returns the attachment of the attachment
+// option by path from the resolved data of the
+// interaction in ctx.
+//
+// If the attachment has not been resolved,
+// ErrOptionNotResolved is returned.
+func (co CommandOptions) Attachment(ctx Context, path string) (v *discordgo.MessageAttachment, err error) {
+	id, err := co.idValue(path, discordgo.ApplicationCommandOptionAttachment)
+	if err != nil {
+		return
+	}
+	v, ok := resolvedData(ctx).Attachments[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: attachment %s", ErrOptionNotResolved, id)
+	}
+	return
+}
+
+// AttachmentOr returns the attachment of the attachment
+// option by path or def, if it could not be retrieved.
+func (co CommandOptions) AttachmentOr(
+	ctx Context,
+	path string,
+	def *discordgo.MessageAttachment,
+) *discordgo.MessageAttachment {
+	v, err := co.Attachment(ctx, path)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// findTyped returns the option by path and checks that
+// it is of the given type.
+func (co CommandOptions) findTyped(
+	path string,
+	typ discordgo.ApplicationCommandOptionType,
+) (opt *CommandOption, err error) {
+	opt, err = co.Find(path)
+	if err != nil {
+		return
+	}
+	if opt.Type != typ {
+		return nil, fmt.Errorf("%w: option %s is of type %s, not %s",
+			ErrOptionTypeMismatch, opt.Name, opt.Type, typ)
+	}
+	return
+}
+
+// idValue returns the snowflake ID value of the option
+// by path of the given type.
+func (co CommandOptions) idValue(path string, typ discordgo.ApplicationCommandOptionType) (id string, err error) {
+	opt, err := co.findTyped(path, typ)
+	if err != nil {
+		return
+	}
+	id, ok := opt.Value.(string)
+	if !ok {
+		return "", opt.valueTypeMismatch()
+	}
+	return
+}
+
+func (o *CommandOption) valueTypeMismatch() error {
+	return fmt.Errorf("%w: option %s has a value of type %T",
+		ErrOptionTypeMismatch, o.Name, o.Value)
+}
+
+// resolvedData returns the resolved data of the
+// application command interaction of ctx. If not
+// available, empty resolved data is returned.
+func resolvedData(ctx Context) *discordgo.ApplicationCommandInteractionDataResolved {
+	if ctx == nil {
+		return &discordgo.ApplicationCommandInteractionDataResolved{}
+	}
+	e := ctx.GetEvent()
+	if e == nil || e.Interaction == nil ||
+		(e.Type != discordgo.InteractionApplicationCommand &&
+			e.Type != discordgo.InteractionApplicationCommandAutocomplete) {
+		return &discordgo.ApplicationCommandInteractionDataResolved{}
+	}
+	if resolved := e.ApplicationCommandData().Resolved; resolved != nil {
+		return resolved
+	}
+	return &discordgo.ApplicationCommandInteractionDataResolved{}
+}
+
+func resolveUser(ctx Context, id string) *discordgo.User {
+	if user, ok := resolvedData(ctx).Users[id]; ok {
+		return user
+	}
+	if ctx != nil {
+		user, err := ctx.GetKen().opt.State.User(ctx.GetSession(), id)
+		if err == nil && user != nil {
+			return user
+		}
+	}
+	return &discordgo.User{ID: id}
+}
+
+func resolveMember(ctx Context, id string) (*discordgo.Member, bool) {
+	resolved := resolvedData(ctx)
+	member, ok := resolved.Members[id]
+	if !ok {
+		return nil, false
+	}
+	// Resolved members do not contain the user object,
+	// so it is added from the resolved users.
+	m := *member
+	if m.User == nil {
+		m.User = resolved.Users[id]
+	}
+	if m.GuildID == "" {
+		m.GuildID = ctx.GetEvent().GuildID
+	}
+	return &m, true
+}
+
+func resolveRole(ctx Context, id string) *discordgo.Role {
+	if role, ok := resolvedData(ctx).Roles[id]; ok {
+		return role
+	}
+	if ctx != nil {
+		role, err := ctx.GetKen().opt.State.Role(ctx.GetSession(), ctx.GetEvent().GuildID, id)
+		if err == nil && role != nil {
+			return role
+		}
+	}
+	return &discordgo.Role{ID: id}
+}
+
+func resolveChannel(ctx Context, id string) *discordgo.Channel {
+	if ch, ok := resolvedData(ctx).Channels[id]; ok {
+		return ch
+	}
+	if ctx != nil {
+		ch, err := ctx.GetKen().opt.State.Channel(ctx.GetSession(), id)
+		if err == nil && ch != nil {
+			return ch
+		}
+	}
+	return &discordgo.Channel{ID: id}
+}
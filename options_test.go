@@ -0,0 +1,252 @@
+package ken
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// newTestCtx returns a command context for an
+// application command interaction with the given data.
+func newTestCtx(t *testing.T, data discordgo.ApplicationCommandInteractionData) *Ctx {
+	t.Helper()
+
+	k, err := New(&discordgo.Session{State: discordgo.NewState()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := k.ctxPool.Get()
+	ctx.ken = k
+	ctx.event = &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: "1",
+		Data:    data,
+	}}
+	var cancel func()
+	ctx.ctx, cancel = k.interactionContext(ctx.event.Interaction, interactionTokenLifetime)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func testCommandOptions() CommandOptions {
+	return CommandOptions{
+		{Name: "name", Type: discordgo.ApplicationCommandOptionString, Value: `a\nb`},
+		{Name: "count", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(3)},
+		{Name: "broken", Type: discordgo.ApplicationCommandOptionInteger, Value: "3"},
+		{
+			Name: "config",
+			Type: discordgo.ApplicationCommandOptionSubCommandGroup,
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{
+					Name: "set",
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "channel", Type: discordgo.ApplicationCommandOptionChannel, Value: "10"},
+						{Name: "ratio", Type: discordgo.ApplicationCommandOptionNumber, Value: 0.5},
+						{Name: "enabled", Type: discordgo.ApplicationCommandOptionBoolean, Value: true},
+					},
+				},
+			},
+		},
+		{Name: "user", Type: discordgo.ApplicationCommandOptionUser, Value: "20"},
+		{Name: "role", Type: discordgo.ApplicationCommandOptionRole, Value: "30"},
+		{Name: "mention", Type: discordgo.ApplicationCommandOptionMentionable, Value: "30"},
+		{Name: "unresolved", Type: discordgo.ApplicationCommandOptionMentionable, Value: "99"},
+		{Name: "file", Type: discordgo.ApplicationCommandOptionAttachment, Value: "40"},
+	}
+}
+
+func TestCommandOptionsFind(t *testing.T) {
+	opts := testCommandOptions()
+
+	cases := []struct {
+		path string
+		want string
+		err  error
+	}{
+		{path: "name", want: "name"},
+		{path: "  count ", want: "count"},
+		{path: "config", want: "config"},
+		{path: "config set", want: "set"},
+		{path: "config set channel", want: "channel"},
+		{path: "", err: ErrOptionNotFound},
+		{path: "missing", err: ErrOptionNotFound},
+		{path: "channel", err: ErrOptionNotFound},
+		{path: "config missing channel", err: ErrOptionNotFound},
+		{path: "config set channel id", err: ErrOptionNotFound},
+		{path: "name set", err: ErrOptionNotFound},
+	}
+
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			opt, err := opts.Find(c.path)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("expected error %v, got %v", c.err, err)
+			}
+			if err == nil && opt.Name != c.want {
+				t.Errorf("expected option %s, got %s", c.want, opt.Name)
+			}
+		})
+	}
+}
+
+func TestCommandOptionsTypedValues(t *testing.T) {
+	opts := testCommandOptions()
+	ctx := newTestCtx(t, discordgo.ApplicationCommandInteractionData{
+		Resolved: &discordgo.ApplicationCommandInteractionDataResolved{
+			Users:       map[string]*discordgo.User{"20": {ID: "20", Username: "user"}},
+			Members:     map[string]*discordgo.Member{"20": {Nick: "nick"}},
+			Roles:       map[string]*discordgo.Role{"30": {ID: "30", Name: "role"}},
+			Channels:    map[string]*discordgo.Channel{"10": {ID: "10", Name: "channel"}},
+			Attachments: map[string]*discordgo.MessageAttachment{"40": {ID: "40", Filename: "file.txt"}},
+		},
+	})
+
+	cases := []struct {
+		name string
+		get  func() (interface{}, error)
+		want interface{}
+		err  error
+	}{
+		{
+			name: "string",
+			get:  func() (interface{}, error) { return opts.StringOpt("name") },
+			want: "a\nb",
+		},
+		{
+			name: "integer",
+			get:  func() (interface{}, error) { return opts.Int("count") },
+			want: int64(3),
+		},
+		{
+			name: "number in sub command",
+			get:  func() (interface{}, error) { return opts.Float("config set ratio") },
+			want: 0.5,
+		},
+		{
+			name: "boolean in sub command",
+			get:  func() (interface{}, error) { return opts.Bool("config set enabled") },
+			want: true,
+		},
+		{
+			name: "channel in sub command",
+			get: func() (interface{}, error) {
+				v, err := opts.Channel(ctx, "config set channel")
+				return v.Name, err
+			},
+			want: "channel",
+		},
+		{
+			name: "user",
+			get: func() (interface{}, error) {
+				v, err := opts.User(ctx, "user")
+				return v.Username, err
+			},
+			want: "user",
+		},
+		{
+			name: "member",
+			get: func() (interface{}, error) {
+				v, err := opts.Member(ctx, "user")
+				return v.User.ID + " " + v.Nick + " " + v.GuildID, err
+			},
+			want: "20 nick 1",
+		},
+		{
+			name: "role",
+			get: func() (interface{}, error) {
+				v, err := opts.Role(ctx, "role")
+				return v.Name, err
+			},
+			want: "role",
+		},
+		{
+			name: "mentionable role",
+			get: func() (interface{}, error) {
+				v, err := opts.Mentionable(ctx, "mention")
+				return v.Role != nil && v.User == nil, err
+			},
+			want: true,
+		},
+		{
+			name: "attachment",
+			get: func() (interface{}, error) {
+				v, err := opts.Attachment(ctx, "file")
+				return v.Filename, err
+			},
+			want: "file.txt",
+		},
+		{
+			name: "missing option",
+			get:  func() (interface{}, error) { return opts.StringOpt("missing") },
+			err:  ErrOptionNotFound,
+		},
+		{
+			name: "option of other type",
+			get:  func() (interface{}, error) { return opts.Int("name") },
+			err:  ErrOptionTypeMismatch,
+		},
+		{
+			name: "sub command group as value",
+			get:  func() (interface{}, error) { return opts.StringOpt("config") },
+			err:  ErrOptionTypeMismatch,
+		},
+		{
+			name: "value of other type",
+			get:  func() (interface{}, error) { return opts.Int("broken") },
+			err:  ErrOptionTypeMismatch,
+		},
+		{
+			name: "user as member without resolved member",
+			get: func() (interface{}, error) {
+				return opts.Member(newTestCtx(t, discordgo.ApplicationCommandInteractionData{}), "user")
+			},
+			err: ErrOptionNotResolved,
+		},
+		{
+			name: "unresolved mentionable",
+			get:  func() (interface{}, error) { return opts.Mentionable(ctx, "unresolved") },
+			err:  ErrOptionNotResolved,
+		},
+		{
+			name: "unresolved attachment",
+			get:  func() (interface{}, error) { return opts.Attachment(nil, "file") },
+			err:  ErrOptionNotResolved,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			v, err := c.get()
+			if !errors.Is(err, c.err) {
+				t.Fatalf("expected error %v, got %v", c.err, err)
+			}
+			if err == nil && !reflect.DeepEqual(v, c.want) {
+				t.Errorf("expected %#v, got %#v", c.want, v)
+			}
+		})
+	}
+}
+
+func TestCommandOptionsDefaults(t *testing.T) {
+	opts := testCommandOptions()
+
+	if v := opts.StringOr("name", "def"); v != "a\nb" {
+		t.Errorf("expected the option value, got %q", v)
+	}
+	if v := opts.StringOr("count", "def"); v != "def" {
+		t.Errorf("expected the default for an option of other type, got %q", v)
+	}
+	if v := opts.IntOr("missing", 7); v != 7 {
+		t.Errorf("expected the default for a missing option, got %d", v)
+	}
+	if v := opts.BoolOr("config set enabled", false); !v {
+		t.Error("expected the option value in a sub command")
+	}
+	if v := opts.AttachmentOr(nil, "file", nil); v != nil {
+		t.Errorf("expected the default for an unresolved option, got %+v", v)
+	}
+}
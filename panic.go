@@ -0,0 +1,85 @@
+package ken
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Panic contains information about a panic which has
+// been recovered while handling an interaction.
+type Panic struct {
+	// Context describes the handler which panicked,
+	// for example "command" or "component".
+	Context string
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine
+	// at the time of the panic.
+	Stack []byte
+	// Interaction is the interaction which was
+	// handled when the panic occurred.
+	Interaction *discordgo.Interaction
+}
+
+var _ error = (*Panic)(nil)
+
+func (p *Panic) Error() string {
+	return fmt.Sprintf("panic in %s handler: %v", p.Context, p.Value)
+}
+
+// recoverPanic recovers a panic of the handler and passes
+// it to OnPanic. If r is not nil and the interaction has
+// not been responded to yet or has only been deferred, a
+// generic error message is responded.
+//
+// It must be called directly via defer.
+func (k *Ken) recoverPanic(context string, i *discordgo.Interaction, r *ctxResponder) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	k.opt.OnPanic(&Panic{
+		Context:     context,
+		Value:       v,
+		Stack:       debug.Stack(),
+		Interaction: i,
+	})
+
+	if r == nil {
+		return
+	}
+	if err := r.respondPanicError(); err != nil {
+		k.opt.OnSystemError("panic response", err)
+	}
+}
+
+func (c *ctxResponder) respondPanicError() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// When the interaction has only been deferred, the
+	// deferred response is edited. Its ephemeral state
+	// can not be changed anymore.
+	if c.responded && !c.deferred {
+		return nil
+	}
+	if !c.responded {
+		c.ephemeral = true
+	}
+
+	return c.respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{
+				{
+					Description: c.T(MessageInternalError),
+					Title:       c.T(MessageErrorTitle),
+					Color:       c.ken.opt.EmbedColors.Error,
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,71 @@
+package ken
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestRecoverPanicEditsDeferredResponse(t *testing.T) {
+	var edits []*discordgo.WebhookEdit
+	session, err := discordgo.New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session.Client = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/messages/@original") {
+			var edit discordgo.WebhookEdit
+			if err := json.NewDecoder(r.Body).Decode(&edit); err != nil {
+				t.Error(err)
+			}
+			edits = append(edits, &edit)
+		} else {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader("{}")),
+			Request:    r,
+		}, nil
+	})}
+
+	var recovered *Panic
+	k, err := New(session, WithOnPanic(func(p *Panic) { recovered = p }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &ctxResponder{
+		ken:       k,
+		session:   session,
+		event:     &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{AppID: "1", Token: "token"}},
+		responded: true,
+		deferred:  true,
+	}
+
+	func() {
+		defer k.recoverPanic("command", r.event.Interaction, r)
+		panic("test")
+	}()
+
+	if recovered == nil || recovered.Value != "test" {
+		t.Fatalf("expected panic to be passed to OnPanic, got %+v", recovered)
+	}
+	if len(edits) != 1 || edits[0].Embeds == nil || len(*edits[0].Embeds) != 1 {
+		t.Fatalf("expected deferred response to be edited with an error embed, got %d edits", len(edits))
+	}
+	if desc := (*edits[0].Embeds)[0].Description; desc != r.T(MessageInternalError) {
+		t.Fatalf("unexpected error message %q", desc)
+	}
+}
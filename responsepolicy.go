@@ -1,5 +1,7 @@
 package ken
 
+import "time"
+
 // ResponsePolicy describes rules for context
 // followups and responses.
 type ResponsePolicy struct {
@@ -12,6 +14,17 @@ type ResponsePolicy struct {
 	// in your middleware or directly in your command
 	// logic, if you desire.
 	Ephemeral bool
+
+	// When set to a duration greater than zero, the
+	// interaction is automatically responded to with
+	// a deferred response when the command has not
+	// responded within the given duration.
+	//
+	// Subsequent responses of the command then edit
+	// the deferred response. Because Discord requires
+	// an initial response within three seconds, the
+	// duration should be well below that.
+	AutoDeferAfter time.Duration
 }
 
 // ResponsePolicyCommand defines a command which
@@ -7,6 +7,11 @@ type SlashCommand interface {
 	Command
 
 	// Version returns the commands semantic version.
+	//
+	// When a CommandStore is used which implements
+	// CommandStoreV2, bumping the version ensures that the command
+	// is compared with the registered application command
+	// and updated on the next start.
 	Version() string
 
 	// Options returns an array of application
@@ -16,6 +21,10 @@ type SlashCommand interface {
 
 // DmCapable extends a command to specify if it is
 // able to be executed in DMs or not.
+//
+// This also sets the DMPermission of the application
+// command, so commands which are not DM capable are
+// not displayed in DMs at all.
 type DmCapable interface {
 	// IsDmCapable returns true if the command can
 	// be used in DMs.
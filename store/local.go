@@ -5,13 +5,28 @@ import (
 	"os"
 )
 
+// localStoreVersion is the version of the file
+// format written by LocalCommandStore.
+const localStoreVersion = 2
+
 // LocalCommandStore implements CommandStore for a
 // local file as storage device.
+//
+// Command caches written in the previous format,
+// which only mapped command names to their IDs,
+// are migrated automatically on load.
 type LocalCommandStore struct {
 	loc string
 }
 
-var _ CommandStore = (*LocalCommandStore)(nil)
+var _ CommandStoreV2 = (*LocalCommandStore)(nil)
+
+// localStoreData is the file format of the
+// LocalCommandStore.
+type localStoreData struct {
+	Version  int             `json:"version"`
+	Commands []*CommandEntry `json:"commands"`
+}
 
 // NewLocalCommandStore creates a new instance of
 // LocalCommandStore with the passed file location
@@ -27,25 +42,87 @@ func NewDefault() *LocalCommandStore {
 }
 
 func (lcs *LocalCommandStore) Store(cmds map[string]string) (err error) {
+	entries := make([]*CommandEntry, 0, len(cmds))
+	for key, id := range cmds {
+		e := ParseKey(key)
+		e.ID = id
+		entries = append(entries, e)
+	}
+	return lcs.StoreEntries(entries)
+}
+
+func (lcs *LocalCommandStore) Load() (cmds map[string]string, err error) {
+	entries, err := lcs.LoadEntries()
+	if err != nil {
+		return
+	}
+	cmds = make(map[string]string, len(entries))
+	for _, e := range entries {
+		cmds[e.Key()] = e.ID
+	}
+	return
+}
+
+func (lcs *LocalCommandStore) StoreEntries(entries []*CommandEntry) (err error) {
 	f, err := os.Create(lcs.loc)
 	if err != nil {
 		return
 	}
 	defer f.Close()
-	err = json.NewEncoder(f).Encode(cmds)
+
+	err = json.NewEncoder(f).Encode(localStoreData{
+		Version:  localStoreVersion,
+		Commands: entries,
+	})
 	return
 }
 
-func (lcs *LocalCommandStore) Load() (cmds map[string]string, err error) {
-	cmds = map[string]string{}
-	f, err := os.Open(lcs.loc)
+func (lcs *LocalCommandStore) LoadEntries() (entries []*CommandEntry, err error) {
+	entries = []*CommandEntry{}
+
+	data, err := os.ReadFile(lcs.loc)
 	if err != nil {
 		if os.IsNotExist(err) {
 			err = nil
 		}
 		return
 	}
-	defer f.Close()
-	err = json.NewDecoder(f).Decode(&cmds)
+
+	var raw map[string]json.RawMessage
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return
+	}
+
+	// Legacy files map command keys to their IDs, so
+	// all values are strings. Therefore, a non-string
+	// version value identifies the current format.
+	if v, ok := raw["version"]; ok && len(v) != 0 && v[0] != '"' {
+		var sd localStoreData
+		if err = json.Unmarshal(data, &sd); err != nil {
+			return
+		}
+		if sd.Commands != nil {
+			entries = sd.Commands
+		}
+		return
+	}
+
+	return loadLegacyEntries(data)
+}
+
+// loadLegacyEntries migrates the passed data, which maps
+// command keys to their IDs, to command entries.
+func loadLegacyEntries(data []byte) (entries []*CommandEntry, err error) {
+	var ids map[string]string
+	if err = json.Unmarshal(data, &ids); err != nil {
+		return
+	}
+
+	entries = make([]*CommandEntry, 0, len(ids))
+	for key, id := range ids {
+		e := ParseKey(key)
+		e.ID = id
+		entries = append(entries, e)
+	}
 	return
 }
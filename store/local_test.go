@@ -0,0 +1,142 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestLocalCommandStoreLoadEntries(t *testing.T) {
+	cases := []struct {
+		name  string
+		cache string
+		want  []*CommandEntry
+	}{
+		{
+			name: "no cache file",
+			want: []*CommandEntry{},
+		},
+		{
+			// Written by versions which only keyed the
+			// commands by their name.
+			name:  "baseline cache",
+			cache: `{"ping":"100","version":"101"}` + "\n",
+			want: []*CommandEntry{
+				{ID: "100", Name: "ping", Type: discordgo.ChatApplicationCommand},
+				{ID: "101", Name: "version", Type: discordgo.ChatApplicationCommand},
+			},
+		},
+		{
+			name:  "legacy cache with scopes and types",
+			cache: `{"ping":"100","123:ping":"102","2/info":"103"}` + "\n",
+			want: []*CommandEntry{
+				{ID: "100", Name: "ping", Type: discordgo.ChatApplicationCommand},
+				{ID: "102", Name: "ping", GuildID: "123", Type: discordgo.ChatApplicationCommand},
+				{ID: "103", Name: "info", Type: discordgo.UserApplicationCommand},
+			},
+		},
+		{
+			name:  "empty legacy cache",
+			cache: `{}`,
+			want:  []*CommandEntry{},
+		},
+		{
+			name: "current format",
+			cache: `{"version":2,"commands":[` +
+				`{"id":"100","name":"ping","type":1,"application_id":"9","hash":"abc"},` +
+				`{"id":"103","name":"info","guild_id":"123","type":2,"application_id":"9"}]}`,
+			want: []*CommandEntry{
+				{ID: "100", Name: "ping", Type: discordgo.ChatApplicationCommand, ApplicationID: "9", Hash: "abc"},
+				{ID: "103", Name: "info", GuildID: "123", Type: discordgo.UserApplicationCommand, ApplicationID: "9"},
+			},
+		},
+		{
+			name:  "current format without commands",
+			cache: `{"version":2,"commands":null}`,
+			want:  []*CommandEntry{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			loc := filepath.Join(t.TempDir(), ".commandCache.json")
+			writeTestFile(t, loc, c.cache)
+			got, err := NewLocalCommandStore(loc).LoadEntries()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sortEntries(got)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v, got %+v", formatEntries(c.want), formatEntries(got))
+			}
+		})
+	}
+}
+
+func TestLocalCommandStoreMigration(t *testing.T) {
+	loc := filepath.Join(t.TempDir(), ".commandCache.json")
+	writeTestFile(t, loc, `{"ping":"100","123:ping":"102"}`)
+	lcs := NewLocalCommandStore(loc)
+
+	entries, err := lcs.LoadEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		e.ApplicationID = "9"
+		e.Hash = "hash-" + e.ID
+	}
+	if err = lcs.StoreEntries(entries); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := lcs.LoadEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortEntries(got)
+	want := []*CommandEntry{
+		{ID: "100", Name: "ping", Type: discordgo.ChatApplicationCommand, ApplicationID: "9", Hash: "hash-100"},
+		{ID: "102", Name: "ping", GuildID: "123", Type: discordgo.ChatApplicationCommand, ApplicationID: "9",
+			Hash: "hash-102"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", formatEntries(want), formatEntries(got))
+	}
+
+	ids, err := lcs.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wantIDs := map[string]string{"ping": "100", "123:ping": "102"}; !reflect.DeepEqual(ids, wantIDs) {
+		t.Errorf("expected %v, got %v", wantIDs, ids)
+	}
+}
+
+func writeTestFile(t *testing.T, loc, data string) {
+	t.Helper()
+	if data == "" {
+		return
+	}
+	if err := os.WriteFile(loc, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func sortEntries(entries []*CommandEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+}
+
+func formatEntries(entries []*CommandEntry) []CommandEntry {
+	res := make([]CommandEntry, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, *e)
+	}
+	return res
+}
@@ -1,5 +1,12 @@
 package store
 
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
 // CommandStore allows to store and load registered
 // commands so that they can be updated instead
 // of deleted and re-created on restarts.
@@ -11,3 +18,82 @@ type CommandStore interface {
 	// before.
 	Load() (cmds map[string]string, err error)
 }
+
+// CommandEntry contains the stored metadata of a single
+// registered application command.
+type CommandEntry struct {
+	// ID is the ID of the application command.
+	ID string `json:"id"`
+	// Name is the name of the application command.
+	Name string `json:"name"`
+	// GuildID is the ID of the guild the command is
+	// registered on. It is empty for global commands.
+	GuildID string `json:"guild_id,omitempty"`
+	// Type is the type of the application command.
+	Type discordgo.ApplicationCommandType `json:"type,omitempty"`
+	// ApplicationID is the ID of the application the
+	// command is registered for.
+	ApplicationID string `json:"application_id,omitempty"`
+	// Hash is the definition hash of the command.
+	Hash string `json:"hash,omitempty"`
+}
+
+// Key returns the key of the entry in the maps passed
+// to and returned by a CommandStore.
+//
+// Global slash commands are keyed by their name and
+// guild slash commands by the guild ID and name joined
+// by a colon. The keys of other command types are
+// prefixed with the type followed by a slash.
+func (e *CommandEntry) Key() string {
+	key := e.Name
+	if e.GuildID != "" {
+		key = e.GuildID + ":" + key
+	}
+	if e.Type != 0 && e.Type != discordgo.ChatApplicationCommand {
+		key = strconv.Itoa(int(e.Type)) + "/" + key
+	}
+	return key
+}
+
+// ParseKey returns an entry without ID from the
+// passed key created by CommandEntry.Key.
+func ParseKey(key string) *CommandEntry {
+	e := &CommandEntry{Type: discordgo.ChatApplicationCommand}
+	if i := strings.IndexRune(key, '/'); i != -1 {
+		if t, err := strconv.Atoi(key[:i]); err == nil {
+			e.Type = discordgo.ApplicationCommandType(t)
+			key = key[i+1:]
+		}
+	}
+	if i := strings.IndexRune(key, ':'); i != -1 {
+		if _, err := strconv.ParseUint(key[:i], 10, 64); err == nil {
+			e.GuildID, key = key[:i], key[i+1:]
+		}
+	}
+	e.Name = key
+	return e
+}
+
+// CommandStoreV2 extends CommandStore to store and load
+// registered commands including their scope, type,
+// application ID and definition hash.
+//
+// When the passed CommandStore implements this interface,
+// only StoreEntries and LoadEntries are used. Entries of
+// other applications are kept as they are, so the same
+// store can be shared between multiple applications.
+//
+// Commands are only compared with the registered
+// application commands when their definition hash has
+// changed since the last synchronization.
+type CommandStoreV2 interface {
+	CommandStore
+
+	// StoreEntries stores the passed command entries.
+	StoreEntries(entries []*CommandEntry) error
+	// LoadEntries retrieves the stored command entries
+	// or an empty slice, if no entries were stored
+	// before.
+	LoadEntries() (entries []*CommandEntry, err error)
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestParseKey(t *testing.T) {
+	cases := []struct {
+		key  string
+		want CommandEntry
+	}{
+		{"ping", CommandEntry{Name: "ping", Type: discordgo.ChatApplicationCommand}},
+		{"123:ping", CommandEntry{Name: "ping", GuildID: "123", Type: discordgo.ChatApplicationCommand}},
+		{"2/info", CommandEntry{Name: "info", Type: discordgo.UserApplicationCommand}},
+		{"3/123:quote", CommandEntry{Name: "quote", GuildID: "123", Type: discordgo.MessageApplicationCommand}},
+		{"version", CommandEntry{Name: "version", Type: discordgo.ChatApplicationCommand}},
+		{"1234", CommandEntry{Name: "1234", Type: discordgo.ChatApplicationCommand}},
+		{"foo:ping", CommandEntry{Name: "foo:ping", Type: discordgo.ChatApplicationCommand}},
+		{"foo/ping", CommandEntry{Name: "foo/ping", Type: discordgo.ChatApplicationCommand}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.key, func(t *testing.T) {
+			got := ParseKey(c.key)
+			if !reflect.DeepEqual(*got, c.want) {
+				t.Errorf("expected %+v, got %+v", c.want, *got)
+			}
+			if key := got.Key(); key != c.key {
+				t.Errorf("expected key %q, got %q", c.key, key)
+			}
+		})
+	}
+}
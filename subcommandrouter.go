@@ -0,0 +1,226 @@
+package ken
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SubCommandRouter registers sub commands and sub
+// command groups together with their options and
+// handlers.
+//
+// The application command options of a command can
+// be derived from the router with Options and the
+// invoked sub command is dispatched with Run.
+//
+//	var router = ken.NewSubCommandRouter().
+//		SubCommand("list", "List all entries", listHandler).
+//		Group("config", "Manage the config", func(g *ken.SubCommandGroupRouter) {
+//			g.SubCommand("set", "Set a config value", setHandler, keyOption, valueOption)
+//		})
+//
+//	func (c *MyCommand) Options() []*discordgo.ApplicationCommandOption {
+//		return router.Options()
+//	}
+//
+//	func (c *MyCommand) Run(ctx ken.Context) error {
+//		return router.Run(ctx)
+//	}
+type SubCommandRouter struct {
+	subCommandRoutes
+}
+
+// NewSubCommandRouter returns a new empty
+// SubCommandRouter.
+func NewSubCommandRouter() *SubCommandRouter {
+	return &SubCommandRouter{}
+}
+
+// SubCommand registers a sub command with the given
+// name, description and options. run is called when
+// the sub command has been invoked.
+//
+// It panics if a sub command or group with the same
+// name has already been registered.
+func (r *SubCommandRouter) SubCommand(
+	name, description string,
+	run func(ctx SubCommandContext) error,
+	options ...*discordgo.ApplicationCommandOption,
+) *SubCommandRouter {
+	r.addSubCommand(name, description, run, options)
+	return r
+}
+
+// Group registers a sub command group with the given
+// name and description. The sub commands of the group
+// are registered in build.
+//
+// It panics if a sub command or group with the same
+// name has already been registered.
+func (r *SubCommandRouter) Group(
+	name, description string,
+	build func(g *SubCommandGroupRouter),
+) *SubCommandRouter {
+	g := &SubCommandGroupRouter{}
+	build(g)
+	r.add(&subCommandRoute{
+		option: &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+			Name:        name,
+			Description: description,
+		},
+		group: &g.subCommandRoutes,
+	})
+	return r
+}
+
+// Options returns the application command options
+// of the registered sub commands and groups in the
+// order of their registration.
+func (r *SubCommandRouter) Options() []*discordgo.ApplicationCommandOption {
+	return r.options()
+}
+
+// Run executes the handler of the sub command invoked
+// in ctx.
+//
+// If no handler has been registered for the invoked
+// sub command, ErrSubCommandNotFound is returned.
+func (r *SubCommandRouter) Run(ctx Context) error {
+	return r.run(ctx)
+}
+
+// SubCommandGroupRouter registers the sub commands
+// of a sub command group.
+type SubCommandGroupRouter struct {
+	subCommandRoutes
+}
+
+// SubCommand registers a sub command with the given
+// name, description and options. run is called when
+// the sub command has been invoked.
+//
+// It panics if a sub command with the same name has
+// already been registered.
+func (r *SubCommandGroupRouter) SubCommand(
+	name, description string,
+	run func(ctx SubCommandContext) error,
+	options ...*discordgo.ApplicationCommandOption,
+) *SubCommandGroupRouter {
+	r.addSubCommand(name, description, run, options)
+	return r
+}
+
+type subCommandRoute struct {
+	option *discordgo.ApplicationCommandOption
+	run    func(ctx SubCommandContext) error
+	group  *subCommandRoutes
+}
+
+type subCommandRoutes struct {
+	routes []*subCommandRoute
+}
+
+func (r *subCommandRoutes) addSubCommand(
+	name, description string,
+	run func(ctx SubCommandContext) error,
+	options []*discordgo.ApplicationCommandOption,
+) {
+	if run == nil {
+		panic("ken: nil handler for sub command " + name)
+	}
+	r.add(&subCommandRoute{
+		option: &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        name,
+			Description: description,
+			Options:     options,
+		},
+		run: run,
+	})
+}
+
+func (r *subCommandRoutes) add(route *subCommandRoute) {
+	if route.option.Name == "" {
+		panic("ken: empty sub command name")
+	}
+	if r.get(route.option.Name) != nil {
+		panic("ken: multiple registrations for sub command " + route.option.Name)
+	}
+	r.routes = append(r.routes, route)
+}
+
+func (r *subCommandRoutes) get(name string) *subCommandRoute {
+	for _, route := range r.routes {
+		if route.option.Name == name {
+			return route
+		}
+	}
+	return nil
+}
+
+func (r *subCommandRoutes) options() []*discordgo.ApplicationCommandOption {
+	opts := make([]*discordgo.ApplicationCommandOption, 0, len(r.routes))
+	for _, route := range r.routes {
+		opt := *route.option
+		if route.group != nil {
+			opt.Options = route.group.options()
+		}
+		opts = append(opts, &opt)
+	}
+	return opts
+}
+
+func (r *subCommandRoutes) run(ctx Context) error {
+	opt := findSubCommandOption(ctx.Options())
+	if opt == nil {
+		return fmt.Errorf("%w: %s", ErrSubCommandNotFound, subCommandPathOf(ctx, ""))
+	}
+
+	route := r.get(opt.Name)
+	if route == nil || route.option.Type != opt.Type {
+		return fmt.Errorf("%w: %s", ErrSubCommandNotFound, subCommandPathOf(ctx, opt.Name))
+	}
+
+	if route.group != nil {
+		return runSubCommand(ctx, opt.Name, func(ctx SubCommandContext) error {
+			return route.group.run(ctx)
+		})
+	}
+	return runSubCommand(ctx, opt.Name, route.run)
+}
+
+// findSubCommandOption returns the first sub command
+// or sub command group option in opts or nil, if there
+// is none.
+func findSubCommandOption(opts CommandOptions) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Type == discordgo.ApplicationCommandOptionSubCommand ||
+			opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+			return opt
+		}
+	}
+	return nil
+}
+
+// subCommandPathOf returns the path of the sub command
+// name invoked in ctx, including the command name.
+func subCommandPathOf(ctx Context, name string) string {
+	path := []string{}
+	if ev := ctx.GetEvent(); ev != nil && ev.Interaction != nil &&
+		ev.Type == discordgo.InteractionApplicationCommand {
+		path = append(path, ev.ApplicationCommandData().Name)
+	}
+	if sctx, ok := ctx.(SubCommandContext); ok {
+		path = append(path, sctx.GetSubCommandPath())
+	}
+	if name != "" {
+		path = append(path, name)
+	}
+	return strings.Join(path, " ")
+}
@@ -0,0 +1,176 @@
+package ken
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestSubCommandRouterOptions(t *testing.T) {
+	run := func(ctx SubCommandContext) error { return nil }
+	keyOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "key",
+		Description: "The key",
+	}
+
+	router := NewSubCommandRouter().
+		SubCommand("list", "List all entries", run).
+		Group("config", "Manage the config", func(g *SubCommandGroupRouter) {
+			g.SubCommand("set", "Set a value", run, keyOption)
+		})
+
+	want := []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "List all entries",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+			Name:        "config",
+			Description: "Manage the config",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "set",
+					Description: "Set a value",
+					Options:     []*discordgo.ApplicationCommandOption{keyOption},
+				},
+			},
+		},
+	}
+	if got := router.Options(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected options %s, got %s", mustToJson(want), mustToJson(got))
+	}
+
+	for name, register := range map[string]func(){
+		"duplicate sub command": func() { NewSubCommandRouter().SubCommand("a", "A", run).SubCommand("a", "A", run) },
+		"duplicate group": func() {
+			NewSubCommandRouter().SubCommand("a", "A", run).Group("a", "A", func(*SubCommandGroupRouter) {})
+		},
+		"empty name":  func() { NewSubCommandRouter().SubCommand("", "A", run) },
+		"nil handler": func() { NewSubCommandRouter().SubCommand("a", "A", nil) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected registration to panic")
+				}
+			}()
+			register()
+		})
+	}
+}
+
+func TestSubCommandRouterRun(t *testing.T) {
+	sub := func(name string, opts ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+		return &discordgo.ApplicationCommandInteractionDataOption{
+			Name:    name,
+			Type:    discordgo.ApplicationCommandOptionSubCommand,
+			Options: opts,
+		}
+	}
+	group := func(name string, opts ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+		return &discordgo.ApplicationCommandInteractionDataOption{
+			Name:    name,
+			Type:    discordgo.ApplicationCommandOptionSubCommandGroup,
+			Options: opts,
+		}
+	}
+	key := &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  "key",
+		Type:  discordgo.ApplicationCommandOptionString,
+		Value: "color",
+	}
+
+	var called string
+	handler := func(ctx SubCommandContext) error {
+		called = ctx.GetSubCommandPath() + " " + ctx.Options().StringOr("key", "-")
+		return nil
+	}
+	router := NewSubCommandRouter().
+		SubCommand("list", "List all entries", handler).
+		Group("config", "Manage the config", func(g *SubCommandGroupRouter) {
+			g.SubCommand("set", "Set a value", handler)
+		})
+
+	cases := []struct {
+		name    string
+		options []*discordgo.ApplicationCommandInteractionDataOption
+		want    string
+		err     string
+	}{
+		{
+			name:    "sub command",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{sub("list")},
+			want:    "list -",
+		},
+		{
+			name:    "sub command in group",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{group("config", sub("set", key))},
+			want:    "config set color",
+		},
+		{
+			name: "no sub command",
+			err:  "cmd",
+		},
+		{
+			name:    "unknown sub command",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{sub("delete")},
+			err:     "cmd delete",
+		},
+		{
+			name:    "group invoked as sub command",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{sub("config")},
+			err:     "cmd config",
+		},
+		{
+			name:    "sub command invoked as group",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{group("list", sub("set"))},
+			err:     "cmd list",
+		},
+		{
+			name:    "unknown sub command in group",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{group("config", sub("get"))},
+			err:     "cmd config get",
+		},
+		{
+			name:    "group without sub command",
+			options: []*discordgo.ApplicationCommandInteractionDataOption{group("config", key)},
+			err:     "cmd config",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			called = ""
+			ctx := newTestCtx(t, discordgo.ApplicationCommandInteractionData{
+				Name:    "cmd",
+				Options: c.options,
+			})
+
+			err := router.Run(ctx)
+			if c.err != "" {
+				if !errors.Is(err, ErrSubCommandNotFound) {
+					t.Fatalf("expected ErrSubCommandNotFound, got %v", err)
+				}
+				if want := ErrSubCommandNotFound.Error() + ": " + c.err; err.Error() != want {
+					t.Errorf("expected error %q, got %q", want, err)
+				}
+				if called != "" {
+					t.Errorf("expected no handler to be called, got %q", called)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if called != c.want {
+				t.Errorf("expected %q to be called, got %q", c.want, called)
+			}
+		})
+	}
+}
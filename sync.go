@@ -0,0 +1,589 @@
+package ken
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken/store"
+)
+
+// SyncPlan describes the changes which are required to
+// synchronize the registered commands with the application
+// commands registered on Discord.
+type SyncPlan struct {
+	Scopes []*ScopeSyncPlan `json:"scopes"`
+}
+
+// HasChanges returns true if any scope of the plan
+// contains commands to be created, updated or deleted.
+func (p SyncPlan) HasChanges() bool {
+	for _, sp := range p.Scopes {
+		if sp.HasChanges() {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the parsed JSON data of the
+// SyncPlan.
+func (p SyncPlan) String() string {
+	return mustToJson(p)
+}
+
+// ScopeSyncPlan contains the application commands which
+// would be created, updated or deleted in a single scope.
+//
+// Global commands are represented by an empty GuildID.
+type ScopeSyncPlan struct {
+	GuildID   string                          `json:"guild_id"`
+	Create    []*discordgo.ApplicationCommand `json:"create"`
+	Update    []*discordgo.ApplicationCommand `json:"update"`
+	Delete    []*discordgo.ApplicationCommand `json:"delete"`
+	Unchanged []*discordgo.ApplicationCommand `json:"unchanged"`
+
+	hashes map[commandKey]string
+}
+
+// HasChanges returns true if the scope contains commands
+// to be created, updated or deleted.
+func (p ScopeSyncPlan) HasChanges() bool {
+	return len(p.Create) != 0 || len(p.Update) != 0 || len(p.Delete) != 0
+}
+
+// PlanSync compares the registered commands with the
+// application commands registered on Discord and returns
+// the changes which would be applied on the next sync
+// without actually performing them.
+//
+// The same comparison is used as on command synchronization
+// after the Ready event. Therefore, scopes of which all
+// commands match their stored definition hashes are
+// reported as unchanged without being fetched.
+//
+// Planning does not change the state of Ken, and no lock
+// is held while the scopes are fetched.
+func (k *Ken) PlanSync() (plan *SyncPlan, err error) {
+	appID, err := k.applicationID(k.s)
+	if err != nil {
+		return
+	}
+
+	k.syncMtx.Lock()
+	idcache, _ := k.adoptedIdcache(appID)
+	k.syncMtx.Unlock()
+
+	plan = k.planSync(k.s, appID, idcache, func(_ string, e error) {
+		if err == nil {
+			err = e
+		}
+	})
+	if err != nil {
+		plan = nil
+	}
+	return
+}
+
+// Sync synchronizes the registered commands with the
+// application commands registered on Discord like it is
+// done on the first Ready event. Afterwards, commands
+// are created and deleted immediately when they are
+// registered or unregistered.
+//
+// This must be called when interactions are only received
+// via an InteractionsHandler, because the Session does not
+// receive a Ready event without a gateway connection.
+//
+// Errors occurring for single scopes or commands are
+// passed to OnSystemError.
+func (k *Ken) Sync() (err error) {
+	appID, err := k.applicationID(k.s)
+	if err != nil {
+		return
+	}
+
+	k.syncMtx.Lock()
+	defer k.syncMtx.Unlock()
+
+	k.syncCommands(k.s, appID)
+	return
+}
+
+// syncCommands adopts the idcache for appID, plans the
+// synchronization of all registered commands, applies
+// the resulting changes and stores the idcache.
+//
+// syncMtx must be held by the caller.
+func (k *Ken) syncCommands(s *discordgo.Session, appID string) {
+	k.adoptIdcache(appID)
+
+	plan := k.planSync(s, appID, k.idcache, func(guildID string, err error) {
+		k.opt.OnSystemError("command fetch", err, guildID)
+	})
+
+	for _, sp := range plan.Scopes {
+		k.applyScopeSyncPlan(s, appID, sp)
+	}
+
+	k.appID = appID
+	k.storeIdcache()
+}
+
+// planSync groups all registered commands by their
+// scope and compares each scope with the application
+// commands registered on Discord.
+//
+// Guild scopes which only occur in the passed idcache
+// are planned as well, so that commands which have been
+// removed from a guild are deleted from it.
+//
+// Scopes which could not be fetched are reported to
+// onError and are omitted from the plan.
+func (k *Ken) planSync(
+	s *discordgo.Session,
+	appID string,
+	idcache map[commandKey]*store.CommandEntry,
+	onError func(guildID string, err error),
+) *SyncPlan {
+	scopes := map[string]map[commandKey]Command{
+		"": {},
+	}
+
+	// Commands are only read under the lock, so that
+	// interactions can be dispatched while the scopes
+	// are fetched.
+	k.cmdsLock.RLock()
+	for ck, cmd := range k.cmds {
+		if _, ok := scopes[ck.GuildID]; !ok {
+			scopes[ck.GuildID] = make(map[commandKey]Command)
+		}
+		scopes[ck.GuildID][ck] = cmd
+	}
+	k.cmdsLock.RUnlock()
+
+	for ck := range idcache {
+		if _, ok := scopes[ck.GuildID]; !ok {
+			scopes[ck.GuildID] = make(map[commandKey]Command)
+		}
+	}
+
+	plan := &SyncPlan{
+		Scopes: make([]*ScopeSyncPlan, 0, len(scopes)),
+	}
+	for guildID, cmds := range scopes {
+		sp, ok := k.planScopeFromHashes(guildID, cmds, idcache)
+		if !ok {
+			var err error
+			sp, err = k.planScope(s, appID, guildID, cmds)
+			if err != nil {
+				onError(guildID, err)
+				continue
+			}
+		}
+		plan.Scopes = append(plan.Scopes, sp)
+	}
+
+	sort.Slice(plan.Scopes, func(i, j int) bool {
+		return plan.Scopes[i].GuildID < plan.Scopes[j].GuildID
+	})
+
+	return plan
+}
+
+// planScope fetches the application commands currently
+// registered in the given scope and compares them field
+// by field with the generated application command
+// representation of the passed commands.
+func (k *Ken) planScope(
+	s *discordgo.Session,
+	appID, guildID string,
+	cmds map[commandKey]Command,
+) (sp *ScopeSyncPlan, err error) {
+	registered, err := s.ApplicationCommands(appID, guildID)
+	if err != nil {
+		return
+	}
+
+	registeredByKey := make(map[commandKey]*discordgo.ApplicationCommand, len(registered))
+	for _, rcmd := range registered {
+		registeredByKey[applicationCommandKey(guildID, rcmd)] = rcmd
+	}
+
+	sp = newScopeSyncPlan(guildID)
+
+	for ck, cmd := range cmds {
+		acmd := k.scopedApplicationCommand(cmd, guildID)
+		sp.hashes[ck] = applicationCommandHash(acmd)
+
+		rcmd, ok := registeredByKey[ck]
+		if !ok {
+			sp.Create = append(sp.Create, acmd)
+			continue
+		}
+
+		acmd.ID = rcmd.ID
+		if applicationCommandsEqual(acmd, rcmd) {
+			sp.Unchanged = append(sp.Unchanged, acmd)
+		} else {
+			sp.Update = append(sp.Update, acmd)
+		}
+	}
+
+	for ck, rcmd := range registeredByKey {
+		if _, ok := cmds[ck]; !ok {
+			sp.Delete = append(sp.Delete, rcmd)
+		}
+	}
+
+	for _, acmds := range [][]*discordgo.ApplicationCommand{sp.Create, sp.Update, sp.Delete, sp.Unchanged} {
+		sortApplicationCommands(acmds)
+	}
+
+	return
+}
+
+// planScopeFromHashes returns a plan for the given scope
+// containing all passed commands as unchanged when all
+// of them have been registered before and their stored
+// definition hashes match their current definitions.
+//
+// If this is not the case or the CommandStore does not
+// store hashes, ok is false and the scope must be
+// compared with the registered application commands.
+func (k *Ken) planScopeFromHashes(
+	guildID string,
+	cmds map[commandKey]Command,
+	idcache map[commandKey]*store.CommandEntry,
+) (sp *ScopeSyncPlan, ok bool) {
+	if !k.hashesStored {
+		return
+	}
+
+	for ck := range idcache {
+		if _, registered := cmds[ck]; ck.GuildID == guildID && !registered {
+			return
+		}
+	}
+
+	sp = newScopeSyncPlan(guildID)
+
+	for ck, cmd := range cmds {
+		e, cached := idcache[ck]
+		if !cached {
+			return nil, false
+		}
+
+		acmd := k.scopedApplicationCommand(cmd, guildID)
+		hash := applicationCommandHash(acmd)
+		if e.Hash != hash {
+			return nil, false
+		}
+
+		acmd.ID = e.ID
+		sp.Unchanged = append(sp.Unchanged, acmd)
+		sp.hashes[ck] = hash
+	}
+
+	sortApplicationCommands(sp.Unchanged)
+
+	return sp, true
+}
+
+// applyScopeSyncPlan creates, updates and deletes the
+// application commands as planned and updates the
+// idcache accordingly.
+func (k *Ken) applyScopeSyncPlan(s *discordgo.Session, appID string, sp *ScopeSyncPlan) {
+	for ck := range k.idcache {
+		if ck.GuildID == sp.GuildID {
+			delete(k.idcache, ck)
+		}
+	}
+
+	for _, acmd := range sp.Unchanged {
+		k.cacheCommand(appID, sp.GuildID, acmd, acmd.ID, sp.hashes[applicationCommandKey(sp.GuildID, acmd)])
+	}
+
+	for _, acmd := range sp.Create {
+		ccmd, err := s.ApplicationCommandCreate(appID, sp.GuildID, acmd)
+		if err != nil {
+			k.opt.OnSystemError("command registration", err, acmd.Name)
+			continue
+		}
+		k.cacheCommand(appID, sp.GuildID, acmd, ccmd.ID, sp.hashes[applicationCommandKey(sp.GuildID, acmd)])
+	}
+
+	// Updates are sent as a create, which overwrites the
+	// command with the same name, because an edit leaves
+	// fields unchanged which are omitted as unset.
+	for _, acmd := range sp.Update {
+		_, err := s.ApplicationCommandCreate(appID, sp.GuildID, acmd)
+		if err != nil {
+			k.opt.OnSystemError("command update", err, acmd.Name)
+			// The hash is not cached so that the
+			// update is retried on the next sync.
+			k.cacheCommand(appID, sp.GuildID, acmd, acmd.ID, "")
+			continue
+		}
+		k.cacheCommand(appID, sp.GuildID, acmd, acmd.ID, sp.hashes[applicationCommandKey(sp.GuildID, acmd)])
+	}
+
+	for _, rcmd := range sp.Delete {
+		err := s.ApplicationCommandDelete(appID, sp.GuildID, rcmd.ID)
+		if err != nil {
+			k.opt.OnSystemError("command delete", err, rcmd.Name)
+			// The command is kept without hash so that the
+			// scope is fetched and the delete is retried on
+			// the next sync.
+			k.cacheCommand(appID, sp.GuildID, rcmd, rcmd.ID, "")
+		}
+	}
+}
+
+// scopedApplicationCommand returns the application
+// command representation of cmd for the given scope.
+func (k *Ken) scopedApplicationCommand(cmd Command, guildID string) *discordgo.ApplicationCommand {
+	acmd := k.toApplicationCommand(cmd)
+	if guildID != "" {
+		// These fields are only supported
+		// for global commands.
+		acmd.DMPermission = nil
+		acmd.Contexts = nil
+		acmd.IntegrationTypes = nil
+	}
+	return acmd
+}
+
+// applicationCommandHash returns a hash of the definition
+// of the passed application command. This includes the
+// Version of the command, so changing the version always
+// results in a different hash.
+func applicationCommandHash(acmd *discordgo.ApplicationCommand) string {
+	data, _ := json.Marshal(acmd)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func newScopeSyncPlan(guildID string) *ScopeSyncPlan {
+	return &ScopeSyncPlan{
+		GuildID:   guildID,
+		Create:    []*discordgo.ApplicationCommand{},
+		Update:    []*discordgo.ApplicationCommand{},
+		Delete:    []*discordgo.ApplicationCommand{},
+		Unchanged: []*discordgo.ApplicationCommand{},
+		hashes:    make(map[commandKey]string),
+	}
+}
+
+// applicationID returns the ID of the application which
+// is the ID of the authenticated bot user. When the user
+// is not available from the state, it is fetched from
+// the API.
+func (k *Ken) applicationID(s *discordgo.Session) (string, error) {
+	self, err := k.opt.State.SelfUser(s)
+	if err == nil && self != nil {
+		return self.ID, nil
+	}
+	self, err = s.User("@me")
+	if err != nil {
+		return "", err
+	}
+	return self.ID, nil
+}
+
+func sortApplicationCommands(acmds []*discordgo.ApplicationCommand) {
+	sort.Slice(acmds, func(i, j int) bool {
+		if acmds[i].Type != acmds[j].Type {
+			return acmds[i].Type < acmds[j].Type
+		}
+		return acmds[i].Name < acmds[j].Name
+	})
+}
+
+// applicationCommandsEqual returns true when the local
+// application command a does not differ from the
+// registered application command b in any field which
+// is relevant for the command definition.
+//
+// The Version field is ignored because it is managed
+// by Discord.
+func applicationCommandsEqual(a, b *discordgo.ApplicationCommand) bool {
+	return a.Type == b.Type &&
+		a.Name == b.Name &&
+		a.Description == b.Description &&
+		localizationsEqual(derefLocalizations(a.NameLocalizations), derefLocalizations(b.NameLocalizations)) &&
+		localizationsEqual(derefLocalizations(a.DescriptionLocalizations), derefLocalizations(b.DescriptionLocalizations)) &&
+		int64PtrEqual(a.DefaultMemberPermissions, b.DefaultMemberPermissions) &&
+		boolPtrEqual(a.DMPermission, b.DMPermission, true) &&
+		boolPtrEqual(a.NSFW, b.NSFW, false) &&
+		sliceSetEqual(derefSlice(a.Contexts, nil), derefSlice(b.Contexts, nil)) &&
+		sliceSetEqual(
+			derefSlice(a.IntegrationTypes, defaultIntegrationTypes),
+			derefSlice(b.IntegrationTypes, defaultIntegrationTypes)) &&
+		optionsEqual(a.Options, b.Options)
+}
+
+var defaultIntegrationTypes = []discordgo.ApplicationIntegrationType{
+	discordgo.ApplicationIntegrationGuildInstall,
+}
+
+func optionsEqual(a, b []*discordgo.ApplicationCommandOption) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !optionEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func optionEqual(a, b *discordgo.ApplicationCommandOption) bool {
+	return a.Type == b.Type &&
+		a.Name == b.Name &&
+		a.Description == b.Description &&
+		localizationsEqual(a.NameLocalizations, b.NameLocalizations) &&
+		localizationsEqual(a.DescriptionLocalizations, b.DescriptionLocalizations) &&
+		channelTypesEqual(a.ChannelTypes, b.ChannelTypes) &&
+		a.Required == b.Required &&
+		a.Autocomplete == b.Autocomplete &&
+		choicesEqual(a.Choices, b.Choices) &&
+		float64PtrEqual(a.MinValue, b.MinValue) &&
+		a.MaxValue == b.MaxValue &&
+		intPtrEqual(a.MinLength, b.MinLength) &&
+		a.MaxLength == b.MaxLength &&
+		optionsEqual(a.Options, b.Options)
+}
+
+func choicesEqual(a, b []*discordgo.ApplicationCommandOptionChoice) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name ||
+			!localizationsEqual(a[i].NameLocalizations, b[i].NameLocalizations) ||
+			!choiceValuesEqual(a[i].Value, b[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// choiceValuesEqual compares two choice values. Because
+// values decoded from the API are always float64 for
+// numeric choices, numbers are compared by their
+// formatted value.
+func choiceValuesEqual(a, b interface{}) bool {
+	if isNumber(a) && isNumber(b) {
+		return fmt.Sprint(toFloat64(a)) == fmt.Sprint(toFloat64(b))
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func channelTypesEqual(a, b []discordgo.ChannelType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func localizationsEqual(a, b map[discordgo.Locale]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for locale, v := range a {
+		if bv, ok := b[locale]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+func derefLocalizations(m *map[discordgo.Locale]string) map[discordgo.Locale]string {
+	if m == nil {
+		return nil
+	}
+	return *m
+}
+
+func derefSlice[T any](s *[]T, def []T) []T {
+	if s == nil {
+		return def
+	}
+	return *s
+}
+
+// sliceSetEqual returns true if a and b contain the
+// same elements regardless of their order.
+func sliceSetEqual[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[T]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		if counts[v] == 0 {
+			return false
+		}
+		counts[v]--
+	}
+	return true
+}
+
+func boolPtrEqual(a, b *bool, def bool) bool {
+	av, bv := def, def
+	if a != nil {
+		av = *a
+	}
+	if b != nil {
+		bv = *b
+	}
+	return av == bv
+}
+
+func int64PtrEqual(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func intPtrEqual(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func float64PtrEqual(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return true
+	}
+	return false
+}
+
+func toFloat64(v interface{}) float64 {
+	return reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0))).Float()
+}
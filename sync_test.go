@@ -0,0 +1,999 @@
+package ken
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/zekrotja/ken/store"
+)
+
+func TestApplicationCommandsEqual(t *testing.T) {
+	boolPtr := func(v bool) *bool { return &v }
+	int64Ptr := func(v int64) *int64 { return &v }
+	float64Ptr := func(v float64) *float64 { return &v }
+	contexts := func(v ...discordgo.InteractionContextType) *[]discordgo.InteractionContextType { return &v }
+	integrationTypes := func(v ...discordgo.ApplicationIntegrationType) *[]discordgo.ApplicationIntegrationType {
+		return &v
+	}
+
+	ping := func() *discordgo.ApplicationCommand {
+		return &discordgo.ApplicationCommand{
+			Type:         discordgo.ChatApplicationCommand,
+			Name:         "ping",
+			Description:  "Ping pong",
+			DMPermission: boolPtr(false),
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "message",
+					Description: "The message",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "count",
+					Description: "The count",
+					MinValue:    float64Ptr(0),
+					MaxValue:    10,
+				},
+			},
+		}
+	}
+
+	// pingPayload is the response of the API for ping.
+	// Unset fields are either omitted or null.
+	const pingPayload = `{
+		"id": "1", "application_id": "2", "version": "3",
+		"default_member_permissions": null,
+		"type": 1, "name": "ping", "name_localizations": null,
+		"description": "Ping pong", "description_localizations": null,
+		"dm_permission": false, "contexts": null, "integration_types": [0], "nsfw": false,
+		"options": [
+			{"type": 3, "name": "message", "description": "The message", "required": true},
+			{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10}
+		]
+	}`
+
+	cases := []struct {
+		name    string
+		local   func() *discordgo.ApplicationCommand
+		payload string
+		want    bool
+	}{
+		{
+			name:    "unchanged command",
+			local:   ping,
+			payload: pingPayload,
+			want:    true,
+		},
+		{
+			name: "empty localizations equal omitted localizations",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.NameLocalizations = &map[discordgo.Locale]string{}
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong",
+				"name_localizations": {}, "description_localizations": {},
+				"dm_permission": false,
+				"options": [
+					{"type": 3, "name": "message", "description": "The message", "required": true,
+					 "name_localizations": {}, "description_localizations": {}},
+					{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10}
+				]
+			}`,
+			want: true,
+		},
+		{
+			name: "changed localization",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.NameLocalizations = &map[discordgo.Locale]string{discordgo.German: "pingen"}
+				return acmd
+			},
+			payload: pingPayload,
+			want:    false,
+		},
+		{
+			name: "omitted required of optional option",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.Options[0].Required = false
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong", "dm_permission": false,
+				"options": [
+					{"type": 3, "name": "message", "description": "The message"},
+					{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10}
+				]
+			}`,
+			want: true,
+		},
+		{
+			name: "changed required",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.Options[0].Required = false
+				return acmd
+			},
+			payload: pingPayload,
+			want:    false,
+		},
+		{
+			name: "changed description",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.Description = "Ping"
+				return acmd
+			},
+			payload: pingPayload,
+			want:    false,
+		},
+		{
+			name: "added option",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.Options = append(acmd.Options, &discordgo.ApplicationCommandOption{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "silent",
+					Description: "Silent",
+				})
+				return acmd
+			},
+			payload: pingPayload,
+			want:    false,
+		},
+		{
+			name: "integer and float choice values",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.Options[1].Choices = []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "one", Value: 1},
+					{Name: "half", Value: 0.5},
+					{Name: "large", Value: int64(1) << 40},
+				}
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong", "dm_permission": false,
+				"options": [
+					{"type": 3, "name": "message", "description": "The message", "required": true},
+					{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10,
+					 "choices": [
+						{"name": "one", "value": 1},
+						{"name": "half", "value": 0.5},
+						{"name": "large", "value": 1099511627776}
+					 ]}
+				]
+			}`,
+			want: true,
+		},
+		{
+			name: "changed choice value",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.Options[1].Choices = []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "one", Value: 2},
+				}
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong", "dm_permission": false,
+				"options": [
+					{"type": 3, "name": "message", "description": "The message", "required": true},
+					{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10,
+					 "choices": [{"name": "one", "value": 1}]}
+				]
+			}`,
+			want: false,
+		},
+		{
+			name: "string choice value does not equal number",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.Options[0].Choices = []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "one", Value: "1"},
+				}
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong", "dm_permission": false,
+				"options": [
+					{"type": 3, "name": "message", "description": "The message", "required": true,
+					 "choices": [{"name": "one", "value": 1}]},
+					{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10}
+				]
+			}`,
+			want: false,
+		},
+		{
+			name: "default integration types",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.IntegrationTypes = integrationTypes(discordgo.ApplicationIntegrationGuildInstall)
+				return acmd
+			},
+			payload: pingPayload,
+			want:    true,
+		},
+		{
+			name: "integration types in different order",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.IntegrationTypes = integrationTypes(
+					discordgo.ApplicationIntegrationUserInstall,
+					discordgo.ApplicationIntegrationGuildInstall)
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong", "dm_permission": false,
+				"integration_types": [0, 1],
+				"options": [
+					{"type": 3, "name": "message", "description": "The message", "required": true},
+					{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10}
+				]
+			}`,
+			want: true,
+		},
+		{
+			name: "added integration type",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.IntegrationTypes = integrationTypes(
+					discordgo.ApplicationIntegrationGuildInstall,
+					discordgo.ApplicationIntegrationUserInstall)
+				return acmd
+			},
+			payload: pingPayload,
+			want:    false,
+		},
+		{
+			name: "contexts take precedence over dm permission",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.DMPermission = nil
+				acmd.Contexts = contexts(discordgo.InteractionContextGuild)
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong",
+				"dm_permission": true, "contexts": [0],
+				"options": [
+					{"type": 3, "name": "message", "description": "The message", "required": true},
+					{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10}
+				]
+			}`,
+			want: true,
+		},
+		{
+			name: "omitted dm permission defaults to true",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.DMPermission = boolPtr(true)
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong",
+				"options": [
+					{"type": 3, "name": "message", "description": "The message", "required": true},
+					{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10}
+				]
+			}`,
+			want: true,
+		},
+		{
+			name: "changed dm permission",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.DMPermission = boolPtr(true)
+				return acmd
+			},
+			payload: pingPayload,
+			want:    false,
+		},
+		{
+			name: "default member permissions",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.DefaultMemberPermissions = int64Ptr(discordgo.PermissionManageMessages)
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong", "dm_permission": false,
+				"default_member_permissions": "8192",
+				"options": [
+					{"type": 3, "name": "message", "description": "The message", "required": true},
+					{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10}
+				]
+			}`,
+			want: true,
+		},
+		{
+			name: "admin only permissions differ from unset permissions",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.DefaultMemberPermissions = int64Ptr(0)
+				return acmd
+			},
+			payload: pingPayload,
+			want:    false,
+		},
+		{
+			name: "nsfw omitted",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.NSFW = boolPtr(false)
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong", "dm_permission": false,
+				"options": [
+					{"type": 3, "name": "message", "description": "The message", "required": true},
+					{"type": 4, "name": "count", "description": "The count", "min_value": 0, "max_value": 10}
+				]
+			}`,
+			want: true,
+		},
+		{
+			name: "changed min value",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.Options[1].MinValue = float64Ptr(1)
+				return acmd
+			},
+			payload: pingPayload,
+			want:    false,
+		},
+		{
+			name: "removed min value of zero",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.Options[1].MinValue = nil
+				return acmd
+			},
+			payload: pingPayload,
+			want:    false,
+		},
+		{
+			name: "user command without description",
+			local: func() *discordgo.ApplicationCommand {
+				return &discordgo.ApplicationCommand{
+					Type:         discordgo.UserApplicationCommand,
+					Name:         "info",
+					DMPermission: boolPtr(false),
+				}
+			},
+			payload: `{
+				"type": 2, "name": "info", "description": "",
+				"dm_permission": false, "integration_types": [0]
+			}`,
+			want: true,
+		},
+		{
+			name: "sub command options",
+			local: func() *discordgo.ApplicationCommand {
+				acmd := ping()
+				acmd.Options = []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+						Name:        "config",
+						Description: "Config",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionSubCommand,
+								Name:        "set",
+								Description: "Set",
+								Options: []*discordgo.ApplicationCommandOption{
+									{
+										Type:         discordgo.ApplicationCommandOptionChannel,
+										Name:         "channel",
+										Description:  "Channel",
+										ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+									},
+								},
+							},
+						},
+					},
+				}
+				return acmd
+			},
+			payload: `{
+				"type": 1, "name": "ping", "description": "Ping pong", "dm_permission": false,
+				"options": [
+					{"type": 2, "name": "config", "description": "Config", "options": [
+						{"type": 1, "name": "set", "description": "Set", "options": [
+							{"type": 7, "name": "channel", "description": "Channel", "channel_types": [0]}
+						]}
+					]}
+				]
+			}`,
+			want: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var remote discordgo.ApplicationCommand
+			if err := json.Unmarshal([]byte(c.payload), &remote); err != nil {
+				t.Fatal(err)
+			}
+			local := c.local()
+			if got := applicationCommandsEqual(local, &remote); got != c.want {
+				t.Errorf("expected %v, got %v", c.want, got)
+			}
+			if got := applicationCommandsEqual(&remote, local); got != c.want {
+				t.Errorf("expected %v with swapped arguments, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestSyncReplacesUpdatedCommands(t *testing.T) {
+	var bodies []map[string]interface{}
+	k := newTestRestKen(t, func(r *http.Request) (int, string) {
+		switch r.Method {
+		case http.MethodGet:
+			return http.StatusOK, `[{
+				"id": "100", "application_id": "1", "type": 1, "name": "ping",
+				"description": "Ping pong", "default_member_permissions": "8",
+				"nsfw": true, "integration_types": [0]
+			}]`
+		case http.MethodPost:
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			bodies = append(bodies, body)
+			return http.StatusOK, `{"id":"100","name":"ping","type":1}`
+		}
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		return http.StatusNotFound, "{}"
+	})
+
+	k.cmds[newCommandKey(discordgo.ChatApplicationCommand, "", "ping")] = NewSlash("ping").
+		Description("Ping pong").
+		Handler(func(ctx Context) error { return nil })
+
+	k.syncCommands(k.s, k.appID)
+
+	if len(bodies) != 1 {
+		t.Fatalf("expected the command to be replaced once, got %d requests", len(bodies))
+	}
+	for _, field := range []string{"default_member_permissions", "nsfw"} {
+		if v, ok := bodies[0][field]; ok && v != nil && v != false {
+			t.Errorf("expected %s to be reset, got %v", field, v)
+		}
+	}
+}
+
+func TestSyncRetriesFailedDeletes(t *testing.T) {
+	var gets, deletes int
+	k := newTestRestKen(t, func(r *http.Request) (int, string) {
+		switch r.Method {
+		case http.MethodGet:
+			gets++
+			return http.StatusOK, `[
+				{"id": "100", "type": 1, "name": "ping", "description": "Ping pong", "dm_permission": false},
+				{"id": "101", "type": 1, "name": "old", "description": "Old"}
+			]`
+		case http.MethodDelete:
+			deletes++
+			return http.StatusForbidden, `{"code":50001,"message":"Missing Access"}`
+		}
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		return http.StatusNotFound, "{}"
+	})
+	k.hashesStored = true
+
+	k.cmds[newCommandKey(discordgo.ChatApplicationCommand, "", "ping")] = NewSlash("ping").
+		Description("Ping pong").
+		Handler(func(ctx Context) error { return nil })
+
+	k.syncCommands(k.s, k.appID)
+	k.syncCommands(k.s, k.appID)
+
+	if gets != 2 || deletes != 2 {
+		t.Fatalf("expected the scope to be fetched and the delete to be retried, got %d fetches and %d deletes",
+			gets, deletes)
+	}
+}
+
+func TestPlanSyncDoesNotChangeState(t *testing.T) {
+	var k *Ken
+	k = newTestRestKen(t, func(r *http.Request) (int, string) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			return http.StatusNotFound, "{}"
+		}
+		if strings.HasSuffix(r.URL.Path, "/users/@me") {
+			return http.StatusOK, `{"id": "1"}`
+		}
+		if !k.syncMtx.TryLock() {
+			t.Error("expected no lock to be held while fetching")
+		} else {
+			k.syncMtx.Unlock()
+		}
+		return http.StatusOK, `[{"id": "100", "type": 1, "name": "old", "description": "Old"}]`
+	})
+	k.appID = ""
+	k.loadedEntries = []*store.CommandEntry{
+		{ID: "100", Name: "old", Type: discordgo.ChatApplicationCommand},
+		{ID: "200", Name: "other", Type: discordgo.ChatApplicationCommand, ApplicationID: "2"},
+	}
+
+	plan, err := k.PlanSync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Scopes) != 1 || len(plan.Scopes[0].Delete) != 1 {
+		t.Fatalf("expected the stale command to be planned for deletion, got %s", plan)
+	}
+
+	if len(k.loadedEntries) != 2 || len(k.idcache) != 0 || len(k.foreignEntries) != 0 {
+		t.Errorf("expected state to be unchanged, got %d loaded, %d cached and %d foreign entries",
+			len(k.loadedEntries), len(k.idcache), len(k.foreignEntries))
+	}
+}
+
+func TestSyncWithoutReady(t *testing.T) {
+	var created []string
+	k := newTestRestKen(t, func(r *http.Request) (int, string) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/@me"):
+			return http.StatusOK, `{"id": "1"}`
+		case r.Method == http.MethodGet:
+			return http.StatusOK, `[]`
+		case r.Method == http.MethodPost:
+			var acmd discordgo.ApplicationCommand
+			if err := json.NewDecoder(r.Body).Decode(&acmd); err != nil {
+				t.Error(err)
+			}
+			created = append(created, acmd.Name)
+			return http.StatusOK, `{"id": "100"}`
+		}
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		return http.StatusNotFound, "{}"
+	})
+	k.appID = ""
+
+	run := func(ctx Context) error { return nil }
+	if err := k.RegisterCommands(NewSlash("ping").Description("Ping pong").Handler(run)); err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 0 {
+		t.Fatalf("expected no command to be created before sync, got %v", created)
+	}
+
+	if err := k.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.RegisterCommands(NewSlash("pong").Description("Pong ping").Handler(run)); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"ping", "pong"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("expected %v to be created, got %v", want, created)
+	}
+}
+
+// fakeCommandAPI imitates the application command
+// endpoints of the Discord API for the application
+// with the ID "1".
+//
+// Requests are recorded as "METHOD scope [name]" where
+// the scope is "global" or the guild ID. Requests which
+// are contained in fail are answered with an error.
+type fakeCommandAPI struct {
+	t        *testing.T
+	scopes   map[string][]*discordgo.ApplicationCommand
+	fail     map[string]bool
+	requests []string
+	lastID   int
+}
+
+func newFakeCommandAPI(t *testing.T) *fakeCommandAPI {
+	return &fakeCommandAPI{
+		t:      t,
+		scopes: make(map[string][]*discordgo.ApplicationCommand),
+		fail:   make(map[string]bool),
+		lastID: 100,
+	}
+}
+
+// add registers acmd in the given scope and returns
+// its assigned ID.
+func (a *fakeCommandAPI) add(scope string, acmd *discordgo.ApplicationCommand) string {
+	a.lastID++
+	rcmd := *acmd
+	rcmd.ID = strconv.Itoa(a.lastID)
+	rcmd.ApplicationID = "1"
+	a.scopes[scope] = append(a.scopes[scope], &rcmd)
+	return rcmd.ID
+}
+
+// names returns the sorted names of the commands
+// registered in the given scope.
+func (a *fakeCommandAPI) names(scope string) []string {
+	names := []string{}
+	for _, rcmd := range a.scopes[scope] {
+		names = append(names, rcmd.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// takeRequests returns the sorted recorded requests
+// and resets them.
+func (a *fakeCommandAPI) takeRequests() []string {
+	requests := a.requests
+	a.requests = nil
+	sort.Strings(requests)
+	return requests
+}
+
+func (a *fakeCommandAPI) handle(r *http.Request) (int, string) {
+	if strings.HasSuffix(r.URL.Path, "/users/@me") {
+		return http.StatusOK, `{"id": "1"}`
+	}
+
+	_, path, ok := strings.Cut(r.URL.Path, "/applications/1/")
+	if !ok {
+		a.t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		return http.StatusNotFound, "{}"
+	}
+
+	scope := "global"
+	if strings.HasPrefix(path, "guilds/") {
+		scope, path, _ = strings.Cut(strings.TrimPrefix(path, "guilds/"), "/")
+	}
+	_, id, _ := strings.Cut(path, "commands")
+	id = strings.TrimPrefix(id, "/")
+
+	var name string
+	switch r.Method {
+	case http.MethodPost:
+		var acmd discordgo.ApplicationCommand
+		if err := json.NewDecoder(r.Body).Decode(&acmd); err != nil {
+			a.t.Error(err)
+		}
+		name = acmd.Name
+		defer func() {
+			if !a.fail[r.Method+" "+scope+" "+name] {
+				a.upsert(scope, &acmd)
+			}
+		}()
+	case http.MethodDelete:
+		for _, rcmd := range a.scopes[scope] {
+			if rcmd.ID == id {
+				name = rcmd.Name
+			}
+		}
+	}
+
+	request := strings.TrimSpace(r.Method + " " + scope + " " + name)
+	a.requests = append(a.requests, request)
+	if a.fail[request] {
+		return http.StatusForbidden, `{"code": 50001, "message": "Missing Access"}`
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, mustToJson(a.scopes[scope])
+	case http.MethodPost:
+		for _, rcmd := range a.scopes[scope] {
+			if rcmd.Name == name {
+				return http.StatusOK, mustToJson(rcmd)
+			}
+		}
+		return http.StatusOK, fmt.Sprintf(`{"id": "%d", "name": %q}`, a.lastID+1, name)
+	case http.MethodDelete:
+		cmds := a.scopes[scope][:0]
+		for _, rcmd := range a.scopes[scope] {
+			if rcmd.ID != id {
+				cmds = append(cmds, rcmd)
+			}
+		}
+		a.scopes[scope] = cmds
+		return http.StatusNoContent, ""
+	}
+
+	a.t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+	return http.StatusNotFound, "{}"
+}
+
+// upsert replaces the command with the same type and
+// name as acmd in the given scope or adds it, like a
+// create request does.
+func (a *fakeCommandAPI) upsert(scope string, acmd *discordgo.ApplicationCommand) {
+	for i, rcmd := range a.scopes[scope] {
+		if rcmd.Type == acmd.Type && rcmd.Name == acmd.Name {
+			acmd.ID = rcmd.ID
+			acmd.ApplicationID = rcmd.ApplicationID
+			a.scopes[scope][i] = acmd
+			return
+		}
+	}
+	a.add(scope, acmd)
+}
+
+// newSyncTestKen returns a Ken instance using api which
+// has not been synchronized yet, so that commands can
+// be registered without any request.
+func newSyncTestKen(t *testing.T, api *fakeCommandAPI, options ...Option) *Ken {
+	k := newTestRestKen(t, api.handle, options...)
+	k.appID = ""
+	return k
+}
+
+func TestSyncPlansCommandsPerScope(t *testing.T) {
+	api := newFakeCommandAPI(t)
+	k := newSyncTestKen(t, api)
+
+	run := func(ctx Context) error { return nil }
+	ping := NewSlash("ping").Description("Ping pong").Handler(run)
+	err := k.RegisterCommands(
+		ping,
+		NewSlash("conf").Description("Config").Guild("10").Handler(run),
+		NewSlash("multi").Description("Multi").Guilds("10", "30").Handler(run),
+		NewUser("info").Guild("30").Handler(run),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api.add("global", k.scopedApplicationCommand(ping, ""))
+	api.add("10", &discordgo.ApplicationCommand{Type: discordgo.ChatApplicationCommand, Name: "old"})
+	goneID := api.add("20", &discordgo.ApplicationCommand{Type: discordgo.ChatApplicationCommand, Name: "gone"})
+
+	// Guild 20 is only known from the idcache because the
+	// command has been removed from it since the last run.
+	k.loadedEntries = []*store.CommandEntry{
+		{ID: goneID, Name: "gone", GuildID: "20", Type: discordgo.ChatApplicationCommand, ApplicationID: "1"},
+	}
+
+	plan, err := k.PlanSync()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(acmds []*discordgo.ApplicationCommand) []string {
+		names := []string{}
+		for _, acmd := range acmds {
+			names = append(names, acmd.Name)
+		}
+		return names
+	}
+
+	type scopePlan struct {
+		GuildID                           string
+		Create, Update, Delete, Unchanged []string
+	}
+	want := []scopePlan{
+		{GuildID: "", Create: []string{}, Update: []string{}, Delete: []string{}, Unchanged: []string{"ping"}},
+		{GuildID: "10", Create: []string{"conf", "multi"}, Update: []string{}, Delete: []string{"old"}, Unchanged: []string{}},
+		{GuildID: "20", Create: []string{}, Update: []string{}, Delete: []string{"gone"}, Unchanged: []string{}},
+		{GuildID: "30", Create: []string{"multi", "info"}, Update: []string{}, Delete: []string{}, Unchanged: []string{}},
+	}
+	got := make([]scopePlan, 0, len(plan.Scopes))
+	for _, sp := range plan.Scopes {
+		got = append(got, scopePlan{
+			GuildID:   sp.GuildID,
+			Create:    names(sp.Create),
+			Update:    names(sp.Update),
+			Delete:    names(sp.Delete),
+			Unchanged: names(sp.Unchanged),
+		})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected plan %+v, got %+v", want, got)
+	}
+
+	api.takeRequests()
+	if err := k.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRequests := []string{
+		"DELETE 10 old",
+		"DELETE 20 gone",
+		"GET 10",
+		"GET 20",
+		"GET 30",
+		"GET global",
+		"POST 10 conf",
+		"POST 10 multi",
+		"POST 30 info",
+		"POST 30 multi",
+	}
+	if got := api.takeRequests(); !reflect.DeepEqual(got, wantRequests) {
+		t.Errorf("expected requests %v, got %v", wantRequests, got)
+	}
+
+	for scope, want := range map[string][]string{
+		"global": {"ping"},
+		"10":     {"conf", "multi"},
+		"20":     {},
+		"30":     {"info", "multi"},
+	} {
+		if got := api.names(scope); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v to be registered in scope %s, got %v", want, scope, got)
+		}
+	}
+
+	if len(k.idcache) != 5 {
+		t.Errorf("expected 5 cached commands, got %d", len(k.idcache))
+	}
+	for ck, e := range k.idcache {
+		if e.ID == "" || e.Hash == "" {
+			t.Errorf("expected %+v to be cached with ID and hash, got %+v", ck, e)
+		}
+	}
+}
+
+func TestSyncSkipsScopesByHash(t *testing.T) {
+	api := newFakeCommandAPI(t)
+	k := newSyncTestKen(t, api)
+	k.hashesStored = true
+
+	run := func(ctx Context) error { return nil }
+	err := k.RegisterCommands(
+		NewSlash("ping").Description("Ping pong").Handler(run),
+		NewSlash("conf").Description("Config").Guild("10").Handler(run),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectSync := func(want ...string) {
+		t.Helper()
+		k.syncMtx.Lock()
+		k.syncCommands(k.s, "1")
+		k.syncMtx.Unlock()
+		if got := api.takeRequests(); !reflect.DeepEqual(got, want) && len(got)+len(want) != 0 {
+			t.Errorf("expected requests %v, got %v", want, got)
+		}
+	}
+
+	expectSync("GET 10", "GET global", "POST 10 conf", "POST global ping")
+
+	// All hashes match, so no scope is fetched.
+	expectSync()
+
+	k.cmds[newCommandKey(discordgo.ChatApplicationCommand, "10", "conf")] = NewSlash("conf").
+		Description("Configuration").Guild("10").Handler(run)
+	expectSync("GET 10", "POST 10 conf")
+	if got := api.scopes["10"][0].Description; got != "Configuration" {
+		t.Errorf("expected the command to be updated, got description %q", got)
+	}
+
+	// A cached command which is not registered anymore
+	// must be deleted, so the scope is fetched.
+	delete(k.cmds, newCommandKey(discordgo.ChatApplicationCommand, "", "ping"))
+	expectSync("DELETE global ping", "GET global")
+
+	expectSync()
+}
+
+func TestSyncFailures(t *testing.T) {
+	var errs []string
+	api := newFakeCommandAPI(t)
+	k := newSyncTestKen(t, api, WithOnSystemError(func(context string, err error, args ...interface{}) {
+		errs = append(errs, strings.TrimSpace(fmt.Sprintln(append([]interface{}{context}, args...)...)))
+	}))
+	k.hashesStored = true
+
+	run := func(ctx Context) error { return nil }
+	err := k.RegisterCommands(
+		NewSlash("new").Description("New").Handler(run),
+		NewSlash("ping").Description("Ping pong").Handler(run),
+		NewSlash("conf").Description("Config").Guild("10").Handler(run),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pingID := api.add("global", &discordgo.ApplicationCommand{
+		Type: discordgo.ChatApplicationCommand, Name: "ping", Description: "Ping"})
+	oldID := api.add("global", &discordgo.ApplicationCommand{
+		Type: discordgo.ChatApplicationCommand, Name: "old", Description: "Old"})
+	k.loadedEntries = []*store.CommandEntry{
+		{ID: "200", Name: "conf", GuildID: "10", Type: discordgo.ChatApplicationCommand, Hash: "stale"},
+	}
+
+	api.fail["POST global new"] = true
+	api.fail["POST global ping"] = true
+	api.fail["DELETE global old"] = true
+	api.fail["GET 10"] = true
+
+	if err := k.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(errs)
+	wantErrs := []string{
+		"command delete old",
+		"command fetch 10",
+		"command registration new",
+		"command update ping",
+	}
+	if !reflect.DeepEqual(errs, wantErrs) {
+		t.Errorf("expected errors %v, got %v", wantErrs, errs)
+	}
+
+	cases := []struct {
+		name   string
+		key    commandKey
+		cached bool
+		want   store.CommandEntry
+	}{
+		{
+			name: "failed create is not cached",
+			key:  newCommandKey(discordgo.ChatApplicationCommand, "", "new"),
+		},
+		{
+			name:   "failed update is cached without hash",
+			key:    newCommandKey(discordgo.ChatApplicationCommand, "", "ping"),
+			cached: true,
+			want: store.CommandEntry{
+				ID: pingID, Name: "ping", Type: discordgo.ChatApplicationCommand, ApplicationID: "1"},
+		},
+		{
+			name:   "failed delete is cached without hash",
+			key:    newCommandKey(discordgo.ChatApplicationCommand, "", "old"),
+			cached: true,
+			want: store.CommandEntry{
+				ID: oldID, Name: "old", Type: discordgo.ChatApplicationCommand, ApplicationID: "1"},
+		},
+		{
+			name:   "scope which could not be fetched is kept",
+			key:    newCommandKey(discordgo.ChatApplicationCommand, "10", "conf"),
+			cached: true,
+			want: store.CommandEntry{
+				ID: "200", Name: "conf", GuildID: "10", Type: discordgo.ChatApplicationCommand, Hash: "stale"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e, ok := k.idcache[c.key]
+			if ok != c.cached {
+				t.Fatalf("expected cached to be %v, got %v", c.cached, ok)
+			}
+			if ok && *e != c.want {
+				t.Errorf("expected entry %+v, got %+v", c.want, *e)
+			}
+		})
+	}
+
+	// All failed changes are retried on the next sync.
+	api.takeRequests()
+	api.fail = make(map[string]bool)
+	errs = nil
+
+	if err := k.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	wantRequests := []string{
+		"DELETE global old",
+		"GET 10",
+		"GET global",
+		"POST 10 conf",
+		"POST global new",
+		"POST global ping",
+	}
+	if got := api.takeRequests(); !reflect.DeepEqual(got, wantRequests) {
+		t.Errorf("expected requests %v, got %v", wantRequests, got)
+	}
+	if len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
@@ -0,0 +1,142 @@
+package ken
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rs/xid"
+)
+
+// UserError can be returned from commands and
+// middlewares to respond an error message to the
+// user who executed the command.
+type UserError struct {
+	// Message is the content of the error response.
+	Message string
+	// Title is the title of the error response. When
+	// empty, the localized default title is used.
+	Title string
+	// Ephemeral specifies whether the error response
+	// is only visible to the user who executed the
+	// command.
+	Ephemeral bool
+}
+
+var _ error = (*UserError)(nil)
+
+// NewUserError returns a new ephemeral UserError
+// with the given message and optional title.
+func NewUserError(message string, title ...string) *UserError {
+	err := &UserError{
+		Message:   message,
+		Ephemeral: true,
+	}
+	if len(title) != 0 {
+		err.Title = title[0]
+	}
+	return err
+}
+
+func (e *UserError) Error() string {
+	return e.Message
+}
+
+// IncidentError wraps errors which have been responded
+// to the user as unexpected error together with the
+// given incident ID. It is passed to OnCommandError so
+// that the incident ID can be logged.
+type IncidentError struct {
+	// ID is the incident ID which has been sent
+	// to the user.
+	ID string
+	// Err is the original error.
+	Err error
+}
+
+var _ error = (*IncidentError)(nil)
+
+func (e *IncidentError) Error() string {
+	return fmt.Sprintf("[incident %s] %s", e.ID, e.Err.Error())
+}
+
+func (e *IncidentError) Unwrap() error {
+	return e.Err
+}
+
+// DefaultErrorResponder is the default ErrorResponder.
+//
+// When the passed error is or wraps a UserError, its
+// message is responded. Otherwise, a generic error
+// message containing a new incident ID is responded
+// and the error is returned wrapped into an
+// IncidentError.
+//
+// If the interaction has been responded with a deferred
+// response, the response is edited. If it has already
+// been responded otherwise, a follow up message is sent.
+func DefaultErrorResponder(err error, ctx *Ctx) error {
+	var (
+		content   string
+		title     string
+		ephemeral = true
+	)
+
+	var uErr *UserError
+	if errors.As(err, &uErr) {
+		content = uErr.Message
+		title = uErr.Title
+		ephemeral = uErr.Ephemeral
+	} else {
+		id := xid.New().String()
+		content = ctx.T(MessageIncident, id)
+		err = &IncidentError{
+			ID:  id,
+			Err: err,
+		}
+	}
+
+	if title == "" {
+		title = ctx.T(MessageErrorTitle)
+	}
+
+	if rErr := ctx.respondErrorMessage(content, title, ephemeral); rErr != nil {
+		ctx.ken.opt.OnSystemError("error response", rErr)
+	}
+
+	return err
+}
+
+// respondErrorMessage responds the given error message
+// respecting whether the interaction has already been
+// responded to.
+func (c *ctxResponder) respondErrorMessage(content, title string, ephemeral bool) error {
+	emb := &discordgo.MessageEmbed{
+		Description: content,
+		Title:       title,
+		Color:       c.ken.opt.EmbedColors.Error,
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.responded && !c.deferred {
+		c.ephemeral = ephemeral
+		fum := c.FollowUp(true, &discordgo.WebhookParams{
+			Embeds: []*discordgo.MessageEmbed{emb},
+		}).Send()
+		return fum.Error
+	}
+
+	// The ephemeral state of a deferred response can
+	// not be changed anymore.
+	if !c.responded {
+		c.ephemeral = ephemeral
+	}
+	return c.respond(&discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{emb},
+		},
+	})
+}